@@ -1,107 +1,313 @@
 package reducerbuilder
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
 	averagereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/AverageReducer"
+	countreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/CountReducer"
+	counterdeltareducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/CounterDeltaReducer"
+	dedupereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/DedupeReducer"
 	downsamplereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/DownSampleReducer"
+	energyreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/EnergyReducer"
+	firstreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/FirstReducer"
+	lttbreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/LTTBReducer"
+	lastreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/LastReducer"
+	m4reducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/M4Reducer"
 	maxreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/MaxReducer"
 	minreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/MinReducer"
+	quantilereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/QuantileReducer"
+	rangereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/RangeReducer"
+	ratereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/RateReducer"
+	resamplereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/ResampleReducer"
+	stddevreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/StdDevReducer"
 	sumreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/SumReducer"
+	timeweightedaveragereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/TimeWeightedAverageReducer"
+	variancereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/VarianceReducer"
 	"github.com/go-viper/mapstructure/v2"
 )
 
 const (
-	IdAverageReducer    = "average"
-	IdSumReducer        = "sum"
-	IdMaxReducer        = "max"
-	IdMinReducer        = "min"
-	IdDownsampleReducer = "downsample"
+	IdAverageReducer             = "average"
+	IdSumReducer                 = "sum"
+	IdMaxReducer                 = "max"
+	IdMinReducer                 = "min"
+	IdDownsampleReducer          = "downsample"
+	IdLTTBReducer                = "lttb"
+	IdM4Reducer                  = "m4"
+	IdTimeWeightedAverageReducer = "timeweightedaverage"
+	IdEnergyReducer              = "energy"
+	IdCounterDeltaReducer        = "counterdelta"
+	IdRateReducer                = "rate"
+	IdQuantileReducer            = "quantile"
+	IdCountReducer               = "count"
+	IdFirstReducer               = "first"
+	IdLastReducer                = "last"
+	IdRangeReducer               = "range"
+	IdStdDevReducer              = "stddev"
+	IdVarianceReducer            = "variance"
+	IdResampleReducer            = "resample"
+	IdDedupeReducer              = "dedupe"
 )
 
-// reducerRegistry stores the mapping between reducer IDs and their configurations.
-var reducerRegistry = map[string]struct {
-	config      any
+// registryEntry holds how to build a registered reducer.
+type registryEntry struct {
+	newConfig   func() any
 	constructor func(any) (reducer.DataReducer, error)
-}{
-	IdAverageReducer: {
-		config: &averagereducer.Configuration{},
-		constructor: func(c any) (reducer.DataReducer, error) {
-			conf, ok := c.(*averagereducer.Configuration)
-			if !ok {
-				return nil, fmt.Errorf("invalid configuration type for average reducer")
-			}
-			return averagereducer.New(conf)
-		},
-	},
-	IdSumReducer: {
-		config: &sumreducer.Configuration{},
-		constructor: func(c any) (reducer.DataReducer, error) {
-			conf, ok := c.(*sumreducer.Configuration)
-			if !ok {
-				return nil, fmt.Errorf("invalid configuration type for sum reducer")
-			}
-			return sumreducer.New(conf)
-		},
-	},
-	IdMaxReducer: {
-		config: &maxreducer.Configuration{},
-		constructor: func(c any) (reducer.DataReducer, error) {
-			conf, ok := c.(*maxreducer.Configuration)
-			if !ok {
-				return nil, fmt.Errorf("invalid configuration type for max reducer")
-			}
-			return maxreducer.New(conf)
-		},
-	},
-	IdMinReducer: {
-		config: &minreducer.Configuration{},
-		constructor: func(c any) (reducer.DataReducer, error) {
-			conf, ok := c.(*minreducer.Configuration)
-			if !ok {
-				return nil, fmt.Errorf("invalid configuration type for min reducer")
-			}
-			return minreducer.New(conf)
-		},
-	},
-	IdDownsampleReducer: {
-		config: &downsamplereducer.Configuration{},
-		constructor: func(c any) (reducer.DataReducer, error) {
-			conf, ok := c.(*downsamplereducer.Configuration)
+	description string
+	defaults    map[string]any      // Described defaults overriding the default tags
+	enums       map[string][]string // Described values overriding the enum tags
+}
+
+var (
+	registryMu sync.RWMutex
+	// reducerRegistry stores the mapping between reducer IDs and their configurations.
+	reducerRegistry = map[string]registryEntry{}
+)
+
+func init() {
+	mustRegisterTyped(IdAverageReducer, averagereducer.New,
+		"Arithmetic mean of the values over each interval")
+	mustRegisterTyped(IdSumReducer, sumreducer.New,
+		"Sum of the values over each interval",
+		WithDefault("order", reducer.OrderSort), WithDefault("gaps", interval.GapsZero))
+	mustRegisterTyped(IdMaxReducer, maxreducer.New,
+		"Maximum value over each interval")
+	mustRegisterTyped(IdMinReducer, minreducer.New,
+		"Minimum value over each interval")
+	mustRegisterTyped(IdDownsampleReducer, downsamplereducer.New,
+		"Keeps one point out of step",
+		WithDefault("order", reducer.OrderTrust), WithDefault("nan", reducer.NaNPropagate))
+	mustRegisterTyped(IdLTTBReducer, lttbreducer.New,
+		"Largest-Triangle-Three-Buckets downsampling keeping the visual shape of the series",
+		WithDefault("nan", reducer.NaNPropagate))
+	mustRegisterTyped(IdM4Reducer, m4reducer.New,
+		"First, minimum, maximum and last points of each interval or pixel column",
+		WithDefault("nan", reducer.NaNPropagate),
+		WithEnum("gaps", interval.GapsSkip), WithEnum("label", interval.LabelStart))
+	mustRegisterTyped(IdTimeWeightedAverageReducer, timeweightedaveragereducer.New,
+		"Average of the values weighted by how long they were held over each interval",
+		WithEnum("gaps", interval.GapsSkip))
+	mustRegisterTyped(IdEnergyReducer, energyreducer.New,
+		"Integral of the values over each interval, such as power to energy",
+		WithEnum("gaps", interval.GapsSkip))
+	mustRegisterTyped(IdCounterDeltaReducer, counterdeltareducer.New,
+		"Consumption over each interval of a cumulative counter, with reset and rollover detection",
+		WithEnum("gaps", interval.GapsSkip))
+	mustRegisterTyped(IdRateReducer, ratereducer.New,
+		"Rate of change of a counter or gauge, per sample pair or per interval")
+	mustRegisterTyped(IdQuantileReducer, quantilereducer.New,
+		"Quantiles of the values over each interval")
+	mustRegisterTyped(IdCountReducer, countreducer.New,
+		"Number of points in each interval")
+	mustRegisterTyped(IdFirstReducer, firstreducer.New,
+		"First value of each interval")
+	mustRegisterTyped(IdLastReducer, lastreducer.New,
+		"Last value of each interval")
+	mustRegisterTyped(IdRangeReducer, rangereducer.New,
+		"Difference between the maximum and the minimum value over each interval")
+	mustRegisterTyped(IdStdDevReducer, stddevreducer.New,
+		"Population standard deviation of the values over each interval")
+	mustRegisterTyped(IdVarianceReducer, variancereducer.New,
+		"Population variance of the values over each interval")
+	mustRegisterTyped(IdResampleReducer, resamplereducer.New,
+		"Values interpolated on a regular grid",
+		WithEnum("gaps", interval.GapsSkip, interval.GapsNaN), WithEnum("label", interval.LabelStart))
+	mustRegisterTyped(IdDedupeReducer, dedupereducer.New,
+		"Points sharing a timestamp merged into a single point",
+		WithDefault("duplicates", reducer.DuplicatesLast))
+}
+
+// Register makes a reducer available to NewReducer and pipelines under the given id.
+// newConfig must return a pointer to a new configuration, into which the
+// configuration given to NewReducer is decoded before being passed to constructor.
+// It is safe for concurrent use, typically from the init functions of the
+// packages contributing reducers.
+//
+// Parameters:
+//   - id: The unique id of the reducer.
+//   - newConfig: A function returning a pointer to a new, empty configuration.
+//   - constructor: A function building the reducer from a decoded configuration.
+//   - opts: Options such as WithDescription.
+//
+// Returns:
+//   - error: An error if the id is empty or already registered, or if a function is nil.
+func Register(id string, newConfig func() any, constructor func(any) (reducer.DataReducer, error), opts ...RegisterOption) error {
+	if id == "" {
+		return errors.New("reducer id cannot be empty")
+	}
+	if newConfig == nil || constructor == nil {
+		return fmt.Errorf("reducer %s: configuration factory and constructor cannot be nil", id)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := reducerRegistry[id]; exists {
+		return fmt.Errorf("reducer id already registered: %s", id)
+	}
+	entry := registryEntry{newConfig: newConfig, constructor: constructor}
+	for _, opt := range opts {
+		opt(&entry)
+	}
+	reducerRegistry[id] = entry
+	return nil
+}
+
+// RegisterTyped registers a reducer built from a configuration of type C, such
+// as the New function of the built-in reducers. A new C is allocated for every
+// reducer built, so NewReducer can safely be called concurrently.
+//
+// Parameters:
+//   - id: The unique id of the reducer.
+//   - constructor: A function building the reducer from its decoded configuration.
+//   - opts: Options such as WithDescription.
+//
+// Returns:
+//   - error: An error if the id is empty or already registered, or if constructor is nil.
+func RegisterTyped[C any](id string, constructor func(*C) (reducer.DataReducer, error), opts ...RegisterOption) error {
+	if constructor == nil {
+		return fmt.Errorf("reducer %s: constructor cannot be nil", id)
+	}
+	return Register(id,
+		func() any { return new(C) },
+		func(c any) (reducer.DataReducer, error) {
+			conf, ok := c.(*C)
 			if !ok {
-				return nil, fmt.Errorf("invalid configuration type for downsample reducer")
+				return nil, fmt.Errorf("%w: invalid configuration type for %s reducer", reducer.ErrInvalidConfiguration, id)
 			}
-			return downsamplereducer.New(conf)
+			return constructor(conf)
 		},
-	},
+		opts...,
+	)
+}
+
+// mustRegisterTyped registers a built-in reducer and panics on failure.
+// The built-in reducers share the input defaults of reducer.ParseInput and the
+// gaps default of interval.Parse, which opts may override.
+func mustRegisterTyped[C any](id string, constructor func(*C) (reducer.DataReducer, error), description string, opts ...RegisterOption) {
+	opts = append([]RegisterOption{
+		WithDescription(description),
+		WithDefault("order", reducer.OrderReject),
+		WithDefault("duplicates", reducer.DuplicatesKeep),
+		WithDefault("nan", reducer.NaNSkip),
+		WithDefault("gaps", interval.GapsSkip),
+	}, opts...)
+	if err := RegisterTyped(id, constructor, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// unregister removes the reducer registered under id, so that tests can
+// register the same reducers again.
+func unregister(id string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(reducerRegistry, id)
+}
+
+// IDs returns the ids of the registered reducers, in alphabetical order.
+func IDs() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	ids := make([]string, 0, len(reducerRegistry))
+	for id := range reducerRegistry {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
 }
 
 // NewReducer creates a new DataReducer based on the provided id and configuration.
+// Configuration errors are returned as a *reducer.Error naming the reducer and
+// matching reducer.ErrInvalidConfiguration. An unknown id returns an error
+// matching reducer.ErrUnknownReducer.
 func NewReducer(id string, conf any) (reducer.DataReducer, error) {
+	registryMu.RLock()
 	entry, exists := reducerRegistry[id]
+	registryMu.RUnlock()
 	if !exists {
-		return nil, fmt.Errorf("unknown reducer id: %s", id)
+		return nil, fmt.Errorf("%w: %s", reducer.ErrUnknownReducer, id)
 	}
 
-	if err := decodeConfig(conf, entry.config); err != nil {
+	config := entry.newConfig()
+	if err := applyDefaults(config); err != nil {
 		return nil, err
 	}
+	if err := decodeConfig(conf, config); err != nil {
+		return nil, withReducer(id, err)
+	}
+
+	r, err := entry.constructor(config)
+	if err != nil {
+		if !errors.Is(err, reducer.ErrInvalidConfiguration) {
+			err = fmt.Errorf("%w: %w", reducer.ErrInvalidConfiguration, err)
+		}
+		return nil, withReducer(id, err)
+	}
+	return r, nil
+}
 
-	return entry.constructor(entry.config)
+// withReducer returns err as a *reducer.Error naming the reducer id, unless it
+// already names one.
+func withReducer(id string, err error) error {
+	if e, ok := err.(*reducer.Error); ok {
+		if e.Reducer != "" {
+			return err
+		}
+		named := *e
+		named.Reducer = id
+		return &named
+	}
+	return &reducer.Error{Reducer: id, Index: -1, Err: err}
 }
 
 // decodeConfig decodes the input configuration into the result using mapstructure.
+// Decoding is strict: unknown fields are rejected, while values of the wrong
+// type are converted when sensible, such as "5" for an int. Errors name the
+// path of the offending field.
 func decodeConfig(input, result any) error {
+	var metadata mapstructure.Metadata
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-		TagName: "json",
-		Result:  result,
+		TagName:          "json",
+		Result:           result,
+		WeaklyTypedInput: true,
+		Metadata:         &metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create decoder: %w", err)
 	}
 	if err := decoder.Decode(input); err != nil {
-		return fmt.Errorf("failed to decode configuration: %w", err)
+		return fmt.Errorf("%w: %s", reducer.ErrInvalidConfiguration, decodeErrorMessage(err))
+	}
+	if len(metadata.Unused) > 0 {
+		sort.Strings(metadata.Unused)
+		fields := make([]string, len(metadata.Unused))
+		for i, field := range metadata.Unused {
+			fields[i] = strconv.Quote(field)
+		}
+		if len(fields) == 1 {
+			return fmt.Errorf("%w: unknown field %s", reducer.ErrInvalidConfiguration, fields[0])
+		}
+		return fmt.Errorf("%w: unknown fields %s", reducer.ErrInvalidConfiguration, strings.Join(fields, ", "))
 	}
 	return nil
 }
+
+// decodeErrorMessage returns the errors reported by mapstructure on a single line.
+func decodeErrorMessage(err error) string {
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		return err.Error()
+	}
+	var messages []string
+	for _, err := range joined.Unwrap() {
+		messages = append(messages, decodeErrorMessage(err))
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,377 @@
+package reducerbuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	energyreducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/EnergyReducer"
+	"github.com/stretchr/testify/assert"
+)
+
+// scaleConfiguration and scaleReducer are a third-party reducer multiplying values.
+type scaleConfiguration struct {
+	Factor float64 `json:"factor"`
+}
+
+type scaleReducer struct {
+	factor float64
+}
+
+func (sr *scaleReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	reduced := make([]datapoint.TimePoint, len(data))
+	for i, point := range data {
+		reduced[i] = datapoint.TimePoint{Timestamp: point.Timestamp, Value: point.Value * sr.factor}
+	}
+	return reduced, nil
+}
+
+func newScaleConfiguration() any {
+	return &scaleConfiguration{}
+}
+
+func newScaleReducer(c any) (reducer.DataReducer, error) {
+	conf, ok := c.(*scaleConfiguration)
+	if !ok {
+		return nil, errors.New("invalid configuration type for scale reducer")
+	}
+	return &scaleReducer{factor: conf.Factor}, nil
+}
+
+func TestRegister(t *testing.T) {
+	assert.NoError(t, Register("test-scale", newScaleConfiguration, newScaleReducer))
+	t.Cleanup(func() { unregister("test-scale") })
+
+	tests := []struct {
+		name        string
+		id          string
+		newConfig   func() any
+		constructor func(any) (reducer.DataReducer, error)
+	}{
+		{name: "duplicate id", id: "test-scale", newConfig: newScaleConfiguration, constructor: newScaleReducer},
+		{name: "built-in id", id: IdAverageReducer, newConfig: newScaleConfiguration, constructor: newScaleReducer},
+		{name: "empty id", id: "", newConfig: newScaleConfiguration, constructor: newScaleReducer},
+		{name: "nil configuration factory", id: "test-nil-config", constructor: newScaleReducer},
+		{name: "nil constructor", id: "test-nil-constructor", newConfig: newScaleConfiguration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, Register(tt.id, tt.newConfig, tt.constructor))
+		})
+	}
+
+	r, err := NewReducer("test-scale", map[string]any{"factor": 2})
+	assert.NoError(t, err)
+	result, err := r.Reduce([]datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 6}}, result)
+
+	p, err := NewPipeline([]Stage{
+		{ID: IdSumReducer, Config: map[string]any{"interval": "1m"}},
+		{ID: "test-scale", Config: map[string]any{"factor": 0.5}},
+	})
+	assert.NoError(t, err)
+	result, err = p.Reduce([]datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 3},
+		{Timestamp: time.Unix(30, 0), Value: 5},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 4}}, result)
+}
+
+func TestRegisterTyped(t *testing.T) {
+	assert.NoError(t, RegisterTyped("test-typed-scale", func(conf *scaleConfiguration) (reducer.DataReducer, error) {
+		return &scaleReducer{factor: conf.Factor}, nil
+	}))
+	t.Cleanup(func() { unregister("test-typed-scale") })
+	assert.Error(t, RegisterTyped("test-typed-scale", func(conf *scaleConfiguration) (reducer.DataReducer, error) {
+		return &scaleReducer{factor: conf.Factor}, nil
+	}))
+	assert.Error(t, RegisterTyped[scaleConfiguration]("test-typed-nil", nil))
+
+	r, err := NewReducer("test-typed-scale", map[string]any{"factor": 10})
+	assert.NoError(t, err)
+	result, err := r.Reduce([]datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 30}}, result)
+}
+
+func TestNewReducer_Concurrent(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(60, 0), Value: 2},
+		{Timestamp: time.Unix(120, 0), Value: 3},
+		{Timestamp: time.Unix(180, 0), Value: 4},
+	}
+
+	// Each reducer must keep its own configuration, whatever the ones built concurrently
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(minutes int) {
+			defer wg.Done()
+			r, err := NewReducer(IdCountReducer, map[string]any{"interval": fmt.Sprintf("%dm", minutes)})
+			if err != nil {
+				errs <- err
+				return
+			}
+			result, err := r.Reduce(data)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if expected := (len(data) + minutes - 1) / minutes; len(result) != expected {
+				errs <- fmt.Errorf("%dm: got %d points, expected %d", minutes, len(result), expected)
+			}
+		}(i%4 + 1)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func TestIDs(t *testing.T) {
+	ids := IDs()
+	assert.True(t, sort.StringsAreSorted(ids))
+	for _, id := range []string{IdAverageReducer, IdSumReducer, IdMaxReducer, IdMinReducer, IdDownsampleReducer, IdResampleReducer} {
+		assert.Contains(t, ids, id)
+	}
+}
+
+func TestNewReducer(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		conf      any
+		errMsg    string
+		expectErr bool
+	}{
+		{
+			name: "valid configuration",
+			id:   IdAverageReducer,
+			conf: map[string]any{"interval": "1m", "timezone": "Europe/Paris"},
+		},
+		{
+			name: "string to int",
+			id:   IdDownsampleReducer,
+			conf: map[string]any{"step": "5"},
+		},
+		{
+			name: "interval in seconds",
+			id:   IdAverageReducer,
+			conf: map[string]any{"interval": 900},
+		},
+		{
+			name: "single quantile",
+			id:   IdQuantileReducer,
+			conf: map[string]any{"interval": "1m", "quantiles": 0.5},
+		},
+		{
+			name:      "unknown id",
+			id:        "median",
+			conf:      map[string]any{},
+			errMsg:    "unknown reducer id: median",
+			expectErr: true,
+		},
+		{
+			name:      "unknown field",
+			id:        IdAverageReducer,
+			conf:      map[string]any{"intervall": "1m"},
+			errMsg:    `unknown field "intervall"`,
+			expectErr: true,
+		},
+		{
+			name:      "unknown fields",
+			id:        IdAverageReducer,
+			conf:      map[string]any{"interval": "1m", "timzone": "UTC", "aling": "epoch"},
+			errMsg:    `unknown fields "aling", "timzone"`,
+			expectErr: true,
+		},
+		{
+			name:      "invalid type",
+			id:        IdDownsampleReducer,
+			conf:      map[string]any{"step": "five"},
+			errMsg:    "'step'",
+			expectErr: true,
+		},
+		{
+			name:      "invalid item type",
+			id:        IdQuantileReducer,
+			conf:      map[string]any{"interval": "1m", "quantiles": []any{0.5, "high"}},
+			errMsg:    "'quantiles[1]'",
+			expectErr: true,
+		},
+		{
+			name:      "invalid value",
+			id:        IdAverageReducer,
+			conf:      map[string]any{"interval": "1 fortnight"},
+			errMsg:    "invalid interval",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReducer(tt.id, tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, r)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, r)
+			}
+		})
+	}
+}
+
+func TestNewReducer_Errors(t *testing.T) {
+	_, err := NewReducer("median", nil)
+	assert.ErrorIs(t, err, reducer.ErrUnknownReducer)
+	assert.ErrorIs(t, err, reducer.ErrInvalidConfiguration)
+	assert.EqualError(t, err, "unknown reducer id: median")
+
+	_, err = NewReducer(IdAverageReducer, map[string]any{"interval": "-1m"})
+	assert.ErrorIs(t, err, reducer.ErrInvalidInterval)
+	assert.ErrorIs(t, err, reducer.ErrInvalidConfiguration)
+	var e *reducer.Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, IdAverageReducer, e.Reducer)
+		assert.Equal(t, -1, e.Index, "no point is involved")
+	}
+
+	_, err = NewReducer(IdDownsampleReducer, map[string]any{"step": 0})
+	assert.ErrorIs(t, err, reducer.ErrInvalidStep)
+
+	_, err = NewReducer(IdMaxReducer, map[string]any{"intervall": "1m"})
+	assert.ErrorIs(t, err, reducer.ErrInvalidConfiguration)
+	assert.EqualError(t, err, `max reducer: invalid configuration: unknown field "intervall"`)
+
+	// Interval options a reducer cannot apply are rejected rather than ignored
+	_, err = NewReducer(IdCounterDeltaReducer, map[string]any{"interval": "1m", "gaps": "nan"})
+	assert.ErrorIs(t, err, reducer.ErrInvalidConfiguration)
+	assert.EqualError(t, err, `counterdelta reducer: invalid configuration: gaps not supported, got "nan"`)
+
+	// Third-party constructor errors are invalid configurations as well
+	assert.NoError(t, RegisterTyped("test-failing", func(*scaleConfiguration) (reducer.DataReducer, error) {
+		return nil, errors.New("factor must not be zero")
+	}))
+	t.Cleanup(func() { unregister("test-failing") })
+	_, err = NewReducer("test-failing", map[string]any{})
+	assert.ErrorIs(t, err, reducer.ErrInvalidConfiguration)
+	assert.EqualError(t, err, "test-failing reducer: invalid configuration: factor must not be zero")
+}
+
+func TestNewReducer_Defaults(t *testing.T) {
+	r, err := NewReducer(IdEnergyReducer, map[string]any{"interval": "15m"})
+	assert.NoError(t, err)
+	er := r.(*energyreducer.EnergyReducer)
+	assert.Equal(t, energyreducer.MethodTrapezoidal, er.Method)
+	assert.Equal(t, time.Hour, er.Unit)
+	assert.Equal(t, 1.0, er.Scale)
+
+	r, err = NewReducer(IdEnergyReducer, map[string]any{"interval": "15m", "unit": "1s", "scale": 0.001})
+	assert.NoError(t, err)
+	er = r.(*energyreducer.EnergyReducer)
+	assert.Equal(t, time.Second, er.Unit)
+	assert.Equal(t, 0.001, er.Scale)
+}
+
+// builtinIDs are the ids of the built-in reducers.
+var builtinIDs = []string{
+	IdAverageReducer, IdSumReducer, IdMaxReducer, IdMinReducer, IdDownsampleReducer,
+	IdLTTBReducer, IdM4Reducer, IdTimeWeightedAverageReducer, IdEnergyReducer,
+	IdCounterDeltaReducer, IdRateReducer, IdQuantileReducer, IdCountReducer,
+	IdFirstReducer, IdLastReducer, IdRangeReducer, IdStdDevReducer,
+	IdVarianceReducer, IdResampleReducer, IdDedupeReducer,
+}
+
+// builtinConfiguration returns a valid configuration of the built-in reducer id,
+// completed with the given options.
+func builtinConfiguration(id string, options map[string]any) map[string]any {
+	var conf map[string]any
+	switch id {
+	case IdDownsampleReducer:
+		conf = map[string]any{"step": 2}
+	case IdLTTBReducer:
+		conf = map[string]any{"points": 3}
+	case IdQuantileReducer:
+		conf = map[string]any{"interval": "1m", "quantiles": []float64{0.5}}
+	case IdDedupeReducer:
+		conf = map[string]any{}
+	default:
+		conf = map[string]any{"interval": "1m"}
+	}
+	for key, value := range options {
+		conf[key] = value
+	}
+	return conf
+}
+
+func TestNewReducer_Context(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(60, 0), Value: 2},
+		{Timestamp: time.Unix(120, 0), Value: 3},
+		{Timestamp: time.Unix(180, 0), Value: 4},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Every built-in reducer must honour the context
+	for _, id := range builtinIDs {
+		t.Run(id, func(t *testing.T) {
+			r, err := NewReducer(id, builtinConfiguration(id, nil))
+			assert.NoError(t, err)
+			assert.Implements(t, (*reducer.ContextReducer)(nil), r)
+
+			_, err = r.(reducer.ContextReducer).ReduceContext(ctx, data)
+			assert.ErrorIs(t, err, context.Canceled)
+
+			_, err = r.(reducer.ContextReducer).ReduceContext(context.Background(), data)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewReducer_Order(t *testing.T) {
+	unsorted := func() []datapoint.TimePoint {
+		return []datapoint.TimePoint{
+			{Timestamp: time.Unix(120, 0), Value: 3},
+			{Timestamp: time.Unix(0, 0), Value: 1},
+			{Timestamp: time.Unix(180, 0), Value: 4},
+			{Timestamp: time.Unix(60, 0), Value: 2},
+		}
+	}
+
+	// No built-in reducer may reorder its input, whatever the order policy
+	for _, id := range builtinIDs {
+		t.Run(id, func(t *testing.T) {
+			r, err := NewReducer(id, builtinConfiguration(id, map[string]any{"order": reducer.OrderSort}))
+			assert.NoError(t, err)
+			data := unsorted()
+			_, err = r.Reduce(data)
+			assert.NoError(t, err)
+			assert.Equal(t, unsorted(), data)
+
+			r, err = NewReducer(id, builtinConfiguration(id, map[string]any{"order": reducer.OrderReject}))
+			assert.NoError(t, err)
+			_, err = r.Reduce(data)
+			assert.ErrorIs(t, err, reducer.ErrUnsorted)
+			assert.Equal(t, unsorted(), data)
+		})
+	}
+
+	_, err := NewReducer(IdAverageReducer, map[string]any{"interval": "1m", "order": "shuffle"})
+	assert.ErrorIs(t, err, reducer.ErrInvalidConfiguration)
+}
@@ -0,0 +1,326 @@
+package reducerbuilder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+)
+
+// RegisterOption customizes the registration of a reducer.
+type RegisterOption func(*registryEntry)
+
+// WithDescription sets the human readable description of a reducer.
+func WithDescription(description string) RegisterOption {
+	return func(e *registryEntry) {
+		e.description = description
+	}
+}
+
+// WithDefault sets the default value described for the configuration field
+// name, for defaults applied by the constructor rather than by a default tag.
+// It only affects Describe and Schema.
+func WithDefault(name string, value any) RegisterOption {
+	return func(e *registryEntry) {
+		if e.defaults == nil {
+			e.defaults = map[string]any{}
+		}
+		e.defaults[name] = value
+	}
+}
+
+// WithEnum restricts the values described for the configuration field name to
+// the ones accepted by the constructor. It only affects Describe and Schema.
+func WithEnum(name string, values ...string) RegisterOption {
+	return func(e *registryEntry) {
+		if e.enums == nil {
+			e.enums = map[string][]string{}
+		}
+		e.enums[name] = values
+	}
+}
+
+// Description describes a registered reducer and its configuration.
+type Description struct {
+	ID          string  `json:"id"`
+	Description string  `json:"description,omitempty"`
+	Fields      []Field `json:"fields"`
+}
+
+// Field describes a field of a reducer configuration. It is derived from the
+// json tag of the field and from the optional description, default, enum,
+// minimum and required tags.
+type Field struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`            // JSON Schema type
+	Items       string   `json:"items,omitempty"` // JSON Schema type of the items of an array
+	Description string   `json:"description,omitempty"`
+	Default     any      `json:"default,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+}
+
+// Describe returns the description of the reducer registered under id.
+func Describe(id string) (Description, error) {
+	registryMu.RLock()
+	entry, exists := reducerRegistry[id]
+	registryMu.RUnlock()
+	if !exists {
+		return Description{}, fmt.Errorf("%w: %s", reducer.ErrUnknownReducer, id)
+	}
+	fields := describeFields(reflect.TypeOf(entry.newConfig()))
+	for i, f := range fields {
+		if value, ok := entry.defaults[f.Name]; ok {
+			fields[i].Default = value
+		}
+		if values, ok := entry.enums[f.Name]; ok {
+			fields[i].Enum = values
+		}
+	}
+	return Description{
+		ID:          id,
+		Description: entry.description,
+		Fields:      fields,
+	}, nil
+}
+
+// DescribeAll returns the descriptions of all the registered reducers, ordered by id.
+func DescribeAll() []Description {
+	ids := IDs()
+	descriptions := make([]Description, 0, len(ids))
+	for _, id := range ids {
+		if d, err := Describe(id); err == nil {
+			descriptions = append(descriptions, d)
+		}
+	}
+	return descriptions
+}
+
+// Schema returns the JSON Schema of the configuration of the reducer
+// registered under id, ready to be marshalled to JSON.
+func Schema(id string) (map[string]any, error) {
+	d, err := Describe(id)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]any, len(d.Fields))
+	var required []string
+	for _, f := range d.Fields {
+		property := map[string]any{}
+		if f.Type != "" {
+			property["type"] = f.Type
+		}
+		if f.Items != "" {
+			property["items"] = map[string]any{"type": f.Items}
+		}
+		if f.Description != "" {
+			property["description"] = f.Description
+		}
+		if f.Default != nil {
+			property["default"] = f.Default
+		}
+		if len(f.Enum) > 0 {
+			property["enum"] = f.Enum
+		}
+		if f.Minimum != nil {
+			property["minimum"] = *f.Minimum
+		}
+		properties[f.Name] = property
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+
+	schema := map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                d.ID,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if d.Description != "" {
+		schema["description"] = d.Description
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// describeFields returns the fields of a configuration struct, the fields of
+// the structs embedded with the squash option being flattened into it.
+func describeFields(t reflect.Type) []Field {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if squashed(sf) {
+			fields = append(fields, describeFields(sf.Type)...)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		f := Field{
+			Name:        name,
+			Type:        schemaType(sf.Type),
+			Description: sf.Tag.Get("description"),
+			Default:     parseDefault(sf.Type, sf.Tag.Get("default")),
+			Required:    sf.Tag.Get("required") == "true",
+		}
+		if f.Type == "array" {
+			f.Items = schemaType(sf.Type.Elem())
+		}
+		if enum := sf.Tag.Get("enum"); enum != "" {
+			f.Enum = strings.Split(enum, ",")
+		}
+		if minimum, err := strconv.ParseFloat(sf.Tag.Get("minimum"), 64); err == nil {
+			f.Minimum = &minimum
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// squashed reports whether the fields of the embedded struct sf are decoded as
+// fields of the struct embedding it.
+func squashed(sf reflect.StructField) bool {
+	_, options, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	return sf.Anonymous && strings.Contains(","+options+",", ",squash,")
+}
+
+// schemaType returns the JSON Schema type of values of type t.
+func schemaType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Pointer:
+		return schemaType(t.Elem())
+	default:
+		return ""
+	}
+}
+
+// parseDefault converts the default tag of a field to the type of the field.
+// It returns nil when there is no default, and the raw tag when it cannot be converted.
+func parseDefault(t reflect.Type, value string) any {
+	if value == "" {
+		return nil
+	}
+	switch schemaType(t) {
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// applyDefaults sets the fields of the configuration pointed to by config to
+// the value of their default tag, including the fields of the structs embedded
+// with the squash option.
+func applyDefaults(config any) error {
+	v := reflect.ValueOf(config)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if squashed(sf) {
+			if err := applyDefaults(v.Field(i).Addr().Interface()); err != nil {
+				return err
+			}
+			continue
+		}
+		value := sf.Tag.Get("default")
+		if value == "" || !sf.IsExported() || !v.Field(i).IsZero() {
+			continue
+		}
+		if err := setDefault(v.Field(i), value); err != nil {
+			return fmt.Errorf("invalid default for field %s: %w", sf.Name, err)
+		}
+	}
+	return nil
+}
+
+// setDefault parses value into the field f.
+func setDefault(f reflect.Value, value string) error {
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(value, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(x)
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+	return nil
+}
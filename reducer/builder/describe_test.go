@@ -0,0 +1,147 @@
+package reducerbuilder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	d, err := Describe(IdAverageReducer)
+	assert.NoError(t, err)
+	assert.Equal(t, IdAverageReducer, d.ID)
+	assert.NotEmpty(t, d.Description)
+
+	names := make([]string, len(d.Fields))
+	for i, f := range d.Fields {
+		names[i] = f.Name
+	}
+	assert.Equal(t, []string{"interval", "timezone", "align", "origin", "offset", "label", "gaps", "order", "duplicates", "nan"}, names)
+	assert.Equal(t, Field{
+		Name:        "interval",
+		Type:        "string",
+		Description: d.Fields[0].Description,
+		Required:    true,
+	}, d.Fields[0])
+	assert.Equal(t, []string{"start", "end", "center"}, d.Fields[5].Enum)
+	assert.Equal(t, "start", d.Fields[5].Default)
+
+	_, err = Describe("median")
+	assert.ErrorIs(t, err, reducer.ErrUnknownReducer)
+}
+
+func TestDescribe_Registered(t *testing.T) {
+	assert.NoError(t, RegisterTyped("test-described-scale", func(conf *scaleConfiguration) (reducer.DataReducer, error) {
+		return &scaleReducer{factor: conf.Factor}, nil
+	}, WithDescription("Multiplies the values")))
+	t.Cleanup(func() { unregister("test-described-scale") })
+
+	d, err := Describe("test-described-scale")
+	assert.NoError(t, err)
+	assert.Equal(t, Description{
+		ID:          "test-described-scale",
+		Description: "Multiplies the values",
+		Fields:      []Field{{Name: "factor", Type: "number"}},
+	}, d)
+}
+
+func TestDescribeAll(t *testing.T) {
+	descriptions := DescribeAll()
+	ids := IDs()
+	assert.Len(t, descriptions, len(ids))
+	for i, d := range descriptions {
+		assert.Equal(t, ids[i], d.ID)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema, err := Schema(IdQuantileReducer)
+	assert.NoError(t, err)
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, false, schema["additionalProperties"])
+	assert.Equal(t, []string{"interval", "quantiles"}, schema["required"])
+
+	properties := schema["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"type": "number"},
+		"description": "Quantiles to compute, between 0 and 1",
+	}, properties["quantiles"])
+	assert.Equal(t, 100.0, properties["compression"].(map[string]any)["default"])
+	assert.Equal(t, 1.0, properties["compression"].(map[string]any)["minimum"])
+	assert.Equal(t, int64(1024), properties["exact_limit"].(map[string]any)["default"])
+	assert.Equal(t, []string{"exact", "sketch", "auto"}, properties["method"].(map[string]any)["enum"])
+	assert.Contains(t, properties, "timezone")
+
+	_, err = json.Marshal(schema)
+	assert.NoError(t, err)
+
+	for _, id := range IDs() {
+		_, err := Schema(id)
+		assert.NoError(t, err)
+	}
+
+	_, err = Schema("median")
+	assert.Error(t, err)
+}
+
+func TestSchema_Defaults(t *testing.T) {
+	tests := []struct {
+		id           string
+		field        string
+		defaultValue any
+		enum         []string
+	}{
+		{id: IdAverageReducer, field: "order", defaultValue: reducer.OrderReject, enum: []string{"reject", "sort", "trust"}},
+		{id: IdAverageReducer, field: "nan", defaultValue: reducer.NaNSkip},
+		{id: IdAverageReducer, field: "gaps", defaultValue: interval.GapsSkip, enum: []string{"skip", "nan", "zero", "previous", "linear"}},
+		{id: IdSumReducer, field: "order", defaultValue: reducer.OrderSort},
+		{id: IdSumReducer, field: "gaps", defaultValue: interval.GapsZero},
+		{id: IdDownsampleReducer, field: "order", defaultValue: reducer.OrderTrust},
+		{id: IdDownsampleReducer, field: "nan", defaultValue: reducer.NaNPropagate},
+		{id: IdLTTBReducer, field: "nan", defaultValue: reducer.NaNPropagate},
+		{id: IdM4Reducer, field: "gaps", defaultValue: interval.GapsSkip, enum: []string{"skip"}},
+		{id: IdM4Reducer, field: "label", defaultValue: interval.LabelStart, enum: []string{"start"}},
+		{id: IdDedupeReducer, field: "duplicates", defaultValue: reducer.DuplicatesLast},
+		{id: IdResampleReducer, field: "gaps", defaultValue: interval.GapsSkip, enum: []string{"skip", "nan"}},
+		{id: IdEnergyReducer, field: "gaps", defaultValue: interval.GapsSkip, enum: []string{"skip"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id+" "+tt.field, func(t *testing.T) {
+			schema, err := Schema(tt.id)
+			assert.NoError(t, err)
+			property := schema["properties"].(map[string]any)[tt.field].(map[string]any)
+			assert.Equal(t, tt.defaultValue, property["default"])
+			if tt.enum != nil {
+				assert.Equal(t, tt.enum, property["enum"])
+			}
+		})
+	}
+
+	// Every described value of the shared options is accepted by the reducer
+	shared := map[string]bool{"order": true, "duplicates": true, "nan": true, "gaps": true, "label": true}
+	for _, id := range builtinIDs {
+		d, err := Describe(id)
+		assert.NoError(t, err)
+		for _, f := range d.Fields {
+			if !shared[f.Name] {
+				continue
+			}
+			values := append([]any(nil), f.Default)
+			for _, value := range f.Enum {
+				values = append(values, value)
+			}
+			for _, value := range values {
+				if value == nil {
+					continue
+				}
+				_, err := NewReducer(id, builtinConfiguration(id, map[string]any{f.Name: value}))
+				assert.NoError(t, err, "%s: %s %v", id, f.Name, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package reducerbuilder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Definition describes a named reducer in a document read by Load: either a
+// registered reducer id with its configuration, or a pipeline of stages.
+type Definition struct {
+	ID       string  `json:"id"`
+	Config   any     `json:"config"`
+	Pipeline []Stage `json:"pipeline"`
+}
+
+// Load reads a JSON or YAML document mapping names to reducer definitions and
+// builds them all. For example, in YAML:
+//
+//	quarter_peak:
+//	  id: max
+//	  config:
+//	    interval: 15m
+//	hourly_energy:
+//	  pipeline:
+//	    - id: timeweightedaverage
+//	      config: {interval: 1m}
+//	    - id: energy
+//	      config: {interval: 1h}
+//
+// Every definition is validated before returning, so a single call reports all
+// the invalid ones.
+//
+// Parameters:
+//   - r: The reader of the document.
+//   - format: The format of the document, FormatJSON or FormatYAML.
+//
+// Returns:
+//   - map[string]reducer.DataReducer: The reducers built, by name.
+//   - error: An error if the document cannot be read, or joining the errors of every invalid definition.
+func Load(r io.Reader, format string) (map[string]reducer.DataReducer, error) {
+	var document map[string]any
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		if err := json.NewDecoder(r).Decode(&document); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse JSON document: %w", err)
+		}
+	case FormatYAML, "yml":
+		if err := yaml.NewDecoder(r).Decode(&document); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse YAML document: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %q", format)
+	}
+
+	names := make([]string, 0, len(document))
+	for name := range document {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reducers := make(map[string]reducer.DataReducer, len(document))
+	var errs []error
+	for _, name := range names {
+		r, err := newDefinedReducer(document[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reducer %q: %w", name, err))
+			continue
+		}
+		reducers[name] = r
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return reducers, nil
+}
+
+// LoadFile reads the document at path with Load, its format being given by its
+// extension: .json, .yaml or .yml.
+func LoadFile(path string) (map[string]reducer.DataReducer, error) {
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = FormatJSON
+	case ".yaml", ".yml":
+		format = FormatYAML
+	default:
+		return nil, fmt.Errorf("unsupported file extension: %q", filepath.Ext(path))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reducers, err := Load(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return reducers, nil
+}
+
+// newDefinedReducer builds the reducer or pipeline described by a definition.
+func newDefinedReducer(conf any) (reducer.DataReducer, error) {
+	var d Definition
+	if err := decodeConfig(conf, &d); err != nil {
+		return nil, err
+	}
+	if (d.ID == "") == (d.Pipeline == nil) {
+		return nil, fmt.Errorf("%w: exactly one of id or pipeline must be set", reducer.ErrInvalidConfiguration)
+	}
+	if d.Pipeline != nil {
+		return NewPipeline(d.Pipeline)
+	}
+	return NewReducer(d.ID, d.Config)
+}
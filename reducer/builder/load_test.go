@@ -0,0 +1,173 @@
+package reducerbuilder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlDocument = `
+quarter_peak:
+  id: max
+  config:
+    interval: 15m
+    align: epoch
+downsampled:
+  id: downsample
+  config:
+    step: 2
+smoothed_peak:
+  pipeline:
+    - id: average
+      config: {interval: 1m}
+    - id: max
+      config: {interval: 2m}
+`
+
+const jsonDocument = `{
+	"quarter_peak": {"id": "max", "config": {"interval": "15m", "align": "epoch"}},
+	"downsampled": {"id": "downsample", "config": {"step": 2}},
+	"smoothed_peak": {"pipeline": [
+		{"id": "average", "config": {"interval": "1m"}},
+		{"id": "max", "config": {"interval": "2m"}}
+	]}
+}`
+
+func TestLoad(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(30, 0), Value: 3},
+		{Timestamp: time.Unix(60, 0), Value: 5},
+		{Timestamp: time.Unix(90, 0), Value: 7},
+	}
+
+	for _, tt := range []struct {
+		format   string
+		document string
+	}{
+		{format: FormatYAML, document: yamlDocument},
+		{format: FormatJSON, document: jsonDocument},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			reducers, err := Load(strings.NewReader(tt.document), tt.format)
+			assert.NoError(t, err)
+			assert.Len(t, reducers, 3)
+
+			result, err := reducers["quarter_peak"].Reduce(data)
+			assert.NoError(t, err)
+			assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 7}}, result)
+
+			result, err = reducers["downsampled"].Reduce(data)
+			assert.NoError(t, err)
+			assert.Equal(t, []datapoint.TimePoint{data[0], data[2], data[3]}, result)
+
+			result, err = reducers["smoothed_peak"].Reduce(data)
+			assert.NoError(t, err)
+			assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 6}}, result)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		document string
+		errMsgs  []string
+	}{
+		{
+			name:     "unsupported format",
+			format:   "toml",
+			document: "",
+			errMsgs:  []string{`unsupported format: "toml"`},
+		},
+		{
+			name:     "invalid JSON",
+			format:   FormatJSON,
+			document: `{"peak": `,
+			errMsgs:  []string{"failed to parse JSON document"},
+		},
+		{
+			name:     "invalid YAML",
+			format:   FormatYAML,
+			document: "peak: [",
+			errMsgs:  []string{"failed to parse YAML document"},
+		},
+		{
+			name:   "every invalid definition is reported",
+			format: FormatYAML,
+			document: `
+peak:
+  id: max
+  config: {intervall: 15m}
+median:
+  id: median
+both:
+  id: max
+  pipeline: [{id: max, config: {interval: 1m}}]
+broken_pipeline:
+  pipeline:
+    - id: average
+      config: {interval: 1m}
+    - id: max
+      config: {interval: soon}
+valid:
+  id: max
+  config: {interval: 1m}
+`,
+			errMsgs: []string{
+				`reducer "peak": max reducer: invalid configuration: unknown field "intervall"`,
+				`reducer "median": unknown reducer id: median`,
+				`reducer "both": invalid configuration: exactly one of id or pipeline must be set`,
+				`reducer "broken_pipeline": stage 1: max reducer: invalid interval`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reducers, err := Load(strings.NewReader(tt.document), tt.format)
+			assert.Error(t, err)
+			assert.Nil(t, reducers)
+			for _, msg := range tt.errMsgs {
+				assert.Contains(t, err.Error(), msg)
+			}
+			assert.NotContains(t, err.Error(), `"valid"`)
+		})
+	}
+
+	// Invalid definitions are configuration errors, like the reducers they describe
+	_, err := Load(strings.NewReader("empty: {}"), FormatYAML)
+	assert.ErrorIs(t, err, reducer.ErrInvalidConfiguration)
+	_, err = Load(strings.NewReader("median: {id: median}"), FormatYAML)
+	assert.ErrorIs(t, err, reducer.ErrUnknownReducer)
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"policies.yaml": yamlDocument,
+		"policies.yml":  yamlDocument,
+		"policies.json": jsonDocument,
+		"policies.toml": "",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	for _, name := range []string{"policies.yaml", "policies.yml", "policies.json"} {
+		reducers, err := LoadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Len(t, reducers, 3)
+	}
+
+	_, err := LoadFile(filepath.Join(dir, "policies.toml"))
+	assert.Error(t, err)
+	_, err = LoadFile(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package reducerbuilder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// Stage describes a step of a pipeline: the id of a registered reducer and its configuration.
+type Stage struct {
+	ID     string `json:"id"`
+	Config any    `json:"config"`
+}
+
+// Pipeline chains reducers, each stage reducing the output of the previous one.
+// It is itself a DataReducer, so "max 15m of the average 1m" is a pipeline of two stages.
+type Pipeline struct {
+	stages []pipelineStage
+}
+
+type pipelineStage struct {
+	id      string
+	reducer reducer.DataReducer
+}
+
+// NewPipeline creates a Pipeline from an ordered list of stages. The list is
+// decoded like the configuration of a reducer, so either []Stage or a generic
+// list of {"id": ..., "config": ...} maps is accepted.
+//
+// Parameters:
+//   - conf: The ordered list of stages.
+//
+// Returns:
+//   - *Pipeline: A pointer to the newly created Pipeline instance.
+//   - error: An error identifying the stage that could not be built.
+func NewPipeline(conf any) (*Pipeline, error) {
+	var stages []Stage
+	if err := decodeConfig(conf, &stages); err != nil {
+		return nil, err
+	}
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("%w: pipeline must have at least one stage", reducer.ErrInvalidConfiguration)
+	}
+
+	p := &Pipeline{stages: make([]pipelineStage, len(stages))}
+	for i, stage := range stages {
+		r, err := NewReducer(stage.ID, stage.Config)
+		if err != nil {
+			return nil, fmt.Errorf("stage %d: %w", i, err)
+		}
+		p.stages[i] = pipelineStage{id: stage.ID, reducer: r}
+	}
+	return p, nil
+}
+
+// Reduce runs the data through every stage in order and returns the output of
+// the last one. The error of a failing stage is wrapped with its index, as a
+// *reducer.Error naming its reducer id.
+func (p *Pipeline) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return p.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+// Stages are reduced through reducer.ReduceContext.
+func (p *Pipeline) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	for i, stage := range p.stages {
+		reduced, err := reducer.ReduceContext(ctx, stage.reducer, data)
+		if err != nil {
+			return nil, fmt.Errorf("stage %d: %w", i, withReducer(stage.id, err))
+		}
+		data = reduced
+	}
+	return data, nil
+}
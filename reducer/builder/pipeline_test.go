@@ -0,0 +1,153 @@
+package reducerbuilder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPipeline(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      any
+		errMsg    string
+		expectErr bool
+	}{
+		{
+			name: "typed stages",
+			conf: []Stage{
+				{ID: IdAverageReducer, Config: map[string]any{"interval": "1m"}},
+				{ID: IdMaxReducer, Config: map[string]any{"interval": "15m"}},
+			},
+		},
+		{
+			name: "generic stages",
+			conf: []any{
+				map[string]any{"id": IdAverageReducer, "config": map[string]any{"interval": "1m"}},
+			},
+		},
+		{
+			name:      "no stage",
+			conf:      []Stage{},
+			errMsg:    "invalid configuration: pipeline must have at least one stage",
+			expectErr: true,
+		},
+		{
+			name: "unknown id",
+			conf: []Stage{
+				{ID: IdAverageReducer, Config: map[string]any{"interval": "1m"}},
+				{ID: "median", Config: map[string]any{}},
+			},
+			errMsg:    "stage 1: unknown reducer id: median",
+			expectErr: true,
+		},
+		{
+			name: "invalid stage configuration",
+			conf: []Stage{
+				{ID: IdMaxReducer, Config: map[string]any{"interval": "invalid"}},
+			},
+			errMsg:    "stage 0: max reducer: invalid interval",
+			expectErr: true,
+		},
+		{
+			name: "unknown stage field",
+			conf: []any{
+				map[string]any{"id": IdAverageReducer, "confg": map[string]any{"interval": "1m"}},
+			},
+			errMsg:    `unknown field "[0].confg"`,
+			expectErr: true,
+		},
+		{
+			name: "unknown field in a stage configuration",
+			conf: []Stage{
+				{ID: IdMaxReducer, Config: map[string]any{"intervall": "15m"}},
+			},
+			errMsg:    `stage 0: max reducer: invalid configuration: unknown field "intervall"`,
+			expectErr: true,
+		},
+		{
+			name:      "invalid list",
+			conf:      "average",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPipeline(tt.conf)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, reducer.ErrInvalidConfiguration)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, p)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, p)
+			}
+		})
+	}
+}
+
+func TestPipeline_Reduce(t *testing.T) {
+	p, err := NewPipeline([]Stage{
+		{ID: IdAverageReducer, Config: map[string]any{"interval": "1m"}},
+		{ID: IdMaxReducer, Config: map[string]any{"interval": "2m"}},
+	})
+	assert.NoError(t, err)
+
+	result, err := p.Reduce([]datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(30, 0), Value: 3},
+		{Timestamp: time.Unix(60, 0), Value: 5},
+		{Timestamp: time.Unix(90, 0), Value: 7},
+		{Timestamp: time.Unix(120, 0), Value: 4},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 6},
+		{Timestamp: time.Unix(120, 0), Value: 4},
+	}, result)
+
+	_, err = p.Reduce(nil)
+	assert.EqualError(t, err, "stage 0: average reducer: no data to reduce")
+	assert.ErrorIs(t, err, reducer.ErrNoData)
+	var e *reducer.Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, -1, e.Index, "no point is involved")
+	}
+
+	_, err = p.Reduce([]datapoint.TimePoint{
+		{Timestamp: time.Unix(60, 0), Value: 1},
+		{Timestamp: time.Unix(0, 0), Value: 2},
+	})
+	assert.ErrorIs(t, err, reducer.ErrUnsorted)
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, IdAverageReducer, e.Reducer)
+		assert.Equal(t, 1, e.Index)
+		assert.Equal(t, time.Unix(0, 0), e.Timestamp)
+	}
+}
+
+func TestPipeline_ReduceContext(t *testing.T) {
+	p, err := NewPipeline([]Stage{
+		{ID: IdAverageReducer, Config: map[string]any{"interval": "1m"}},
+		{ID: IdMaxReducer, Config: map[string]any{"interval": "2m"}},
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = p.ReduceContext(ctx, []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.EqualError(t, err, "stage 0: average reducer: reduction interrupted: context canceled")
+	var e *reducer.Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, -1, e.Index, "no point is involved")
+	}
+}
@@ -0,0 +1,70 @@
+package reducer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNoData is returned when there is no data to reduce.
+	ErrNoData = errors.New("no data to reduce")
+	// ErrUnsorted is returned when data points are not sorted by timestamp.
+	ErrUnsorted = errors.New("data points must be sorted by timestamp")
+	// ErrDuplicate is returned when data points share a timestamp and duplicates are rejected.
+	ErrDuplicate = errors.New("duplicate timestamp")
+	// ErrInvalidConfiguration is returned when a reducer is configured with
+	// invalid values. ErrInvalidInterval, ErrInvalidStep and ErrUnknownReducer
+	// match it as well.
+	ErrInvalidConfiguration = errors.New("invalid configuration")
+	// ErrInvalidInterval is returned when an interval cannot be parsed or is not positive.
+	ErrInvalidInterval error = &configurationError{"invalid interval"}
+	// ErrInvalidStep is returned when the step of a downsampling is not positive.
+	ErrInvalidStep error = &configurationError{"invalid step value"}
+	// ErrUnknownReducer is returned when no reducer is registered under an id.
+	ErrUnknownReducer error = &configurationError{"unknown reducer id"}
+)
+
+// configurationError is a sentinel error refining ErrInvalidConfiguration.
+type configurationError struct {
+	msg string
+}
+
+func (e *configurationError) Error() string {
+	return e.msg
+}
+
+func (e *configurationError) Unwrap() error {
+	return ErrInvalidConfiguration
+}
+
+// Error describes the failure of a reducer. It tells which reducer failed,
+// when known, and which input point caused the failure, if any.
+//
+// Err is usually one of the sentinel errors of this package, so that callers
+// can match it with errors.Is and get the details with errors.As.
+type Error struct {
+	Reducer   string    // Id of the failing reducer, empty when unknown
+	Index     int       // Index of the offending point in the input data, -1 when unknown
+	Timestamp time.Time // Timestamp of the offending point, zero when no point is involved
+	Err       error
+}
+
+func (e *Error) Error() string {
+	msg := e.Err.Error()
+	switch {
+	case e.Timestamp.IsZero():
+	case e.Index < 0:
+		msg = fmt.Sprintf("%s at %s", msg, e.Timestamp.Format(time.RFC3339Nano))
+	default:
+		msg = fmt.Sprintf("%s at point %d (%s)", msg, e.Index, e.Timestamp.Format(time.RFC3339Nano))
+	}
+	if e.Reducer != "" {
+		msg = fmt.Sprintf("%s reducer: %s", e.Reducer, msg)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
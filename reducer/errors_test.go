@@ -0,0 +1,56 @@
+package reducer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *Error
+		expected string
+	}{
+		{
+			name:     "bare error",
+			err:      &Error{Err: ErrNoData},
+			expected: "no data to reduce",
+		},
+		{
+			name:     "reducer",
+			err:      &Error{Reducer: "average", Err: ErrNoData},
+			expected: "average reducer: no data to reduce",
+		},
+		{
+			name:     "point",
+			err:      &Error{Reducer: "max", Index: 3, Timestamp: time.Unix(60, 0).UTC(), Err: ErrUnsorted},
+			expected: "max reducer: data points must be sorted by timestamp at point 3 (1970-01-01T00:01:00Z)",
+		},
+		{
+			name:     "point of unknown index",
+			err:      &Error{Index: -1, Timestamp: time.Unix(60, 0).UTC(), Err: ErrUnsorted},
+			expected: "data points must be sorted by timestamp at 1970-01-01T00:01:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.err, tt.expected)
+			assert.ErrorIs(t, tt.err, tt.err.Err)
+		})
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	err := fmt.Errorf("%w: must be positive", ErrInvalidInterval)
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+	assert.ErrorIs(t, err, ErrInvalidConfiguration)
+	assert.NotErrorIs(t, err, ErrInvalidStep)
+	assert.EqualError(t, err, "invalid interval: must be positive")
+
+	assert.ErrorIs(t, ErrInvalidStep, ErrInvalidConfiguration)
+	assert.NotErrorIs(t, ErrNoData, ErrInvalidConfiguration)
+}
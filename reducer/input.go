@@ -0,0 +1,381 @@
+package reducer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+const (
+	// OrderReject rejects input data not sorted by timestamp with ErrUnsorted.
+	OrderReject = "reject"
+	// OrderSort reduces a sorted copy of input data not sorted by timestamp.
+	OrderSort = "sort"
+	// OrderTrust skips the order check, leaving it to the caller. Reducers
+	// relying on the order may still fail or return wrong results on unsorted data.
+	OrderTrust = "trust"
+
+	// DuplicatesKeep keeps every point sharing a timestamp.
+	DuplicatesKeep = "keep"
+	// DuplicatesFirst keeps the first of the points sharing a timestamp.
+	DuplicatesFirst = "first"
+	// DuplicatesLast keeps the last of the points sharing a timestamp.
+	DuplicatesLast = "last"
+	// DuplicatesAverage merges the points sharing a timestamp into their average.
+	DuplicatesAverage = "average"
+	// DuplicatesSum merges the points sharing a timestamp into their sum.
+	DuplicatesSum = "sum"
+	// DuplicatesError rejects points sharing a timestamp with ErrDuplicate.
+	DuplicatesError = "error"
+
+	// NaNSkip drops NaN values before reducing, as if they had not been reported.
+	NaNSkip = "skip"
+	// NaNPropagate reduces NaN values like any other, so that every value computed from one is NaN.
+	NaNPropagate = "propagate"
+	// NaNGap treats NaN values as missing data. Reducers computing values from
+	// points drop them as with NaNSkip, while reducers treating data as a
+	// continuous signal leave out the segments going to or from a NaN value.
+	NaNGap = "gap"
+)
+
+// InputOptions holds the policies applied to the input data of a reducer,
+// as embedded in its configuration. Empty values select the reducer defaults.
+type InputOptions struct {
+	Order      string `json:"order" enum:"reject,sort,trust" description:"Handling of input data not sorted by timestamp: reject it, sort a copy of it or trust the caller, the default depending on the reducer"`
+	Duplicates string `json:"duplicates" enum:"keep,first,last,average,sum,error" description:"Handling of points sharing a timestamp: keep them all, keep the first or the last one, merge them into their average or sum, or reject them"`
+	NaN        string `json:"nan" enum:"skip,propagate,gap" description:"Handling of NaN values: drop them, propagate them to the values computed from them, or treat them as missing data, the default depending on the reducer"`
+}
+
+// Input holds the validated policies applied by a reducer to its input data
+// before reducing it. The zero Input rejects unsorted data, keeps duplicates
+// and drops NaN values.
+//
+// The NaN and duplicates policies apply to streams as well, while points
+// pushed to a StreamReducer must always be sorted by timestamp.
+type Input struct {
+	Order      string
+	Duplicates string
+	NaN        string
+}
+
+// ParseInput validates the input options of a reducer.
+//
+// Parameters:
+//   - opts: The input options of the reducer configuration.
+//   - defaultOrder: The order policy used when opts sets none.
+//
+// Returns:
+//   - Input: The validated policies.
+//   - error: An error matching ErrInvalidConfiguration if any of the options is invalid.
+func ParseInput(opts InputOptions, defaultOrder string) (Input, error) {
+	order := opts.Order
+	switch order {
+	case "":
+		order = defaultOrder
+	case OrderReject, OrderSort, OrderTrust:
+	default:
+		return Input{}, fmt.Errorf("%w: invalid order: %q", ErrInvalidConfiguration, opts.Order)
+	}
+
+	duplicates := opts.Duplicates
+	switch duplicates {
+	case "":
+		duplicates = DuplicatesKeep
+	case DuplicatesKeep, DuplicatesFirst, DuplicatesLast, DuplicatesAverage, DuplicatesSum, DuplicatesError:
+	default:
+		return Input{}, fmt.Errorf("%w: invalid duplicates: %q", ErrInvalidConfiguration, opts.Duplicates)
+	}
+
+	nan := opts.NaN
+	switch nan {
+	case "":
+		nan = NaNSkip
+	case NaNSkip, NaNPropagate, NaNGap:
+	default:
+		return Input{}, fmt.Errorf("%w: invalid nan: %q", ErrInvalidConfiguration, opts.NaN)
+	}
+
+	return Input{Order: order, Duplicates: duplicates, NaN: nan}, nil
+}
+
+// Prepare applies the input policies to data and returns the data to reduce.
+// NaN values are dropped under the NaNSkip and NaNGap policies, then points
+// sharing a timestamp are merged once the data is sorted, so only consecutive
+// duplicates are merged when the order is trusted.
+// data itself is never modified: a copy is returned when it has to be changed.
+// Errors referring to a point report its index in data.
+//
+// ErrNoData is returned when no point is left to reduce.
+func (in Input) Prepare(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return in.prepare(data, in.NaN != NaNPropagate)
+}
+
+// PrepareSignal behaves like Prepare, but keeps NaN values under the NaNGap
+// policy. It is meant for reducers treating data as a continuous signal, which
+// leave out the segments going to or from a NaN value.
+func (in Input) PrepareSignal(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return in.prepare(data, in.NaN != NaNPropagate && in.NaN != NaNGap)
+}
+
+func (in Input) prepare(data []datapoint.TimePoint, dropNaN bool) ([]datapoint.TimePoint, error) {
+	if len(data) > 0 && in.prepared(data, dropNaN) {
+		return data, nil
+	}
+	return in.reduce(context.Background(), passStream{}, data, dropNaN)
+}
+
+// prepared reports whether data is left unchanged by the input policies.
+func (in Input) prepared(data []datapoint.TimePoint, dropNaN bool) bool {
+	merge := in.Duplicates != "" && in.Duplicates != DuplicatesKeep
+	for i, point := range data {
+		if dropNaN && math.IsNaN(point.Value) {
+			return false
+		}
+		if i == 0 {
+			continue
+		}
+		if in.Order != OrderTrust && point.Timestamp.Before(data[i-1].Timestamp) {
+			return false
+		}
+		if merge && point.Timestamp.Equal(data[i-1].Timestamp) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reduce applies the input policies to data as Prepare does and reduces the
+// result through stream, checking ctx as ReduceStreamContext does. Errors
+// referring to a point report its index in data, whatever the points sorted,
+// dropped or merged before reaching stream.
+//
+// ErrNoData is returned when no point is left to reduce.
+func (in Input) Reduce(ctx context.Context, stream StreamReducer, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return in.reduce(ctx, stream, data, in.NaN != NaNPropagate)
+}
+
+// ReduceSignal behaves like Reduce, but keeps NaN values under the NaNGap
+// policy, as PrepareSignal does.
+func (in Input) ReduceSignal(ctx context.Context, stream StreamReducer, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return in.reduce(ctx, stream, data, in.NaN != NaNPropagate && in.NaN != NaNGap)
+}
+
+func (in Input) reduce(ctx context.Context, stream StreamReducer, data []datapoint.TimePoint, dropNaN bool) ([]datapoint.TimePoint, error) {
+	if len(data) == 0 {
+		return nil, ErrNoData
+	}
+	data, order, err := in.sort(data)
+	if err != nil {
+		return nil, err
+	}
+	if dropNaN && !hasValue(data) {
+		return nil, ErrNoData
+	}
+
+	reduced, err := ReduceStreamContext(ctx, in.stream(stream, dropNaN), data)
+	if e, ok := err.(*Error); ok && order != nil && e.Index >= 0 && e.Index < len(order) {
+		// The index refers to the sorted copy of data
+		moved := *e
+		moved.Index = order[e.Index]
+		return nil, &moved
+	}
+	return reduced, err
+}
+
+// hasValue reports whether data holds a value other than NaN.
+func hasValue(data []datapoint.TimePoint) bool {
+	for _, point := range data {
+		if !math.IsNaN(point.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sort applies the order policy to data. When data has to be sorted, a sorted
+// copy is returned along with the index in data of each of its points.
+func (in Input) sort(data []datapoint.TimePoint) ([]datapoint.TimePoint, []int, error) {
+	if in.Order == OrderTrust {
+		return data, nil, nil
+	}
+	for i := 1; i < len(data); i++ {
+		if !data[i].Timestamp.Before(data[i-1].Timestamp) {
+			continue
+		}
+		if in.Order != OrderSort {
+			return nil, nil, &Error{Index: i, Timestamp: data[i].Timestamp, Err: ErrUnsorted}
+		}
+		// Points sharing a timestamp keep their relative order
+		order := make([]int, len(data))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return data[order[i]].Timestamp.Before(data[order[j]].Timestamp)
+		})
+		sorted := make([]datapoint.TimePoint, len(data))
+		for i, index := range order {
+			sorted[i] = data[index]
+		}
+		return sorted, order, nil
+	}
+	return data, nil, nil
+}
+
+// Stream returns a StreamReducer applying the NaN and duplicates policies to
+// the pushed points before pushing the remaining ones to stream: NaN values are
+// dropped unless propagated, and consecutive points sharing a timestamp are
+// merged. A merged point reaches stream once a point with another timestamp is
+// pushed, or on Flush. Errors referring to a point report its index among the
+// pushed points.
+func (in Input) Stream(stream StreamReducer) StreamReducer {
+	return in.stream(stream, in.NaN != NaNPropagate)
+}
+
+// StreamSignal behaves like Stream, but keeps NaN values under the NaNGap
+// policy, as PrepareSignal does.
+func (in Input) StreamSignal(stream StreamReducer) StreamReducer {
+	return in.stream(stream, in.NaN != NaNPropagate && in.NaN != NaNGap)
+}
+
+func (in Input) stream(stream StreamReducer, dropNaN bool) StreamReducer {
+	s := &inputStream{stream: stream, dropNaN: dropNaN}
+	if in.Duplicates != "" && in.Duplicates != DuplicatesKeep {
+		s.dedupe = &dedupeStream{duplicates: in.Duplicates}
+	}
+	return s
+}
+
+// inputStream applies the input policies to the points pushed to a StreamReducer.
+type inputStream struct {
+	stream  StreamReducer
+	dropNaN bool
+	dedupe  *dedupeStream // Nil when duplicates are kept
+	index   int           // Index of the next pushed point
+}
+
+func (s *inputStream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	index := s.index
+	s.index++
+	if s.dropNaN && math.IsNaN(point.Value) {
+		return nil, nil
+	}
+	if s.dedupe == nil {
+		return s.push(point, index)
+	}
+	first := s.dedupe.first
+	merged, err := s.dedupe.push(point, index)
+	if err != nil || len(merged) == 0 {
+		return nil, err
+	}
+	return s.push(merged[0], first)
+}
+
+func (s *inputStream) Flush() ([]datapoint.TimePoint, error) {
+	var reduced []datapoint.TimePoint
+	if s.dedupe != nil {
+		first := s.dedupe.first
+		for _, point := range s.dedupe.emit() {
+			out, err := s.push(point, first)
+			if err != nil {
+				return nil, err
+			}
+			reduced = append(reduced, out...)
+		}
+	}
+	out, err := s.stream.Flush()
+	if err != nil {
+		return nil, err
+	}
+	return append(reduced, out...), nil
+}
+
+// push pushes point to the wrapped stream, an error referring to it reporting index.
+func (s *inputStream) push(point datapoint.TimePoint, index int) ([]datapoint.TimePoint, error) {
+	reduced, err := s.stream.Push(point)
+	if e, ok := err.(*Error); ok {
+		moved := *e
+		moved.Index = index
+		return nil, &moved
+	}
+	return reduced, err
+}
+
+// passStream returns the pushed points as is.
+type passStream struct{}
+
+func (passStream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return []datapoint.TimePoint{point}, nil
+}
+
+func (passStream) Flush() ([]datapoint.TimePoint, error) {
+	return nil, nil
+}
+
+// NewDedupeStream returns a StreamReducer merging the consecutive pushed points
+// sharing a timestamp according to the duplicates policy. A merged point is
+// returned once a point with another timestamp is pushed, or by Flush.
+func NewDedupeStream(duplicates string) StreamReducer {
+	return &dedupeStream{duplicates: duplicates}
+}
+
+// dedupeStream tracks the points sharing the timestamp of the last pushed point.
+type dedupeStream struct {
+	duplicates string
+	index      int // Index of the next pushed point
+	first      int // Index of the first point merged into current
+	count      int // Number of points merged into current
+	current    datapoint.TimePoint
+}
+
+func (s *dedupeStream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	index := s.index
+	s.index++
+	return s.push(point, index)
+}
+
+// push merges point, pushed at index, into the current point and returns the
+// previous merged point when point starts a new timestamp.
+func (s *dedupeStream) push(point datapoint.TimePoint, index int) ([]datapoint.TimePoint, error) {
+	if s.duplicates == "" || s.duplicates == DuplicatesKeep {
+		return []datapoint.TimePoint{point}, nil
+	}
+	if s.count == 0 || !point.Timestamp.Equal(s.current.Timestamp) {
+		reduced := s.emit()
+		s.current = point
+		s.first = index
+		s.count = 1
+		return reduced, nil
+	}
+
+	switch s.duplicates {
+	case DuplicatesError:
+		return nil, &Error{Index: index, Timestamp: point.Timestamp, Err: ErrDuplicate}
+	case DuplicatesLast:
+		s.current = point
+	case DuplicatesAverage, DuplicatesSum:
+		s.current.Value += point.Value
+	}
+	s.count++
+	return nil, nil
+}
+
+func (s *dedupeStream) Flush() ([]datapoint.TimePoint, error) {
+	return s.emit(), nil
+}
+
+// emit returns the merged point, if any.
+func (s *dedupeStream) emit() []datapoint.TimePoint {
+	if s.count == 0 {
+		return nil
+	}
+	point := s.current
+	if s.duplicates == DuplicatesAverage {
+		point.Value /= float64(s.count)
+	}
+	s.count = 0
+	return []datapoint.TimePoint{point}
+}
@@ -0,0 +1,391 @@
+package reducer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      InputOptions
+		expected  Input
+		expectErr bool
+	}{
+		{name: "defaults", opts: InputOptions{}, expected: Input{Order: OrderSort, Duplicates: DuplicatesKeep, NaN: NaNSkip}},
+		{name: "reject", opts: InputOptions{Order: OrderReject}, expected: Input{Order: OrderReject, Duplicates: DuplicatesKeep, NaN: NaNSkip}},
+		{name: "trust", opts: InputOptions{Order: OrderTrust}, expected: Input{Order: OrderTrust, Duplicates: DuplicatesKeep, NaN: NaNSkip}},
+		{name: "invalid order", opts: InputOptions{Order: "shuffle"}, expectErr: true},
+		{name: "duplicates", opts: InputOptions{Duplicates: DuplicatesAverage}, expected: Input{Order: OrderSort, Duplicates: DuplicatesAverage, NaN: NaNSkip}},
+		{name: "invalid duplicates", opts: InputOptions{Duplicates: "drop"}, expectErr: true},
+		{name: "nan", opts: InputOptions{NaN: NaNGap}, expected: Input{Order: OrderSort, Duplicates: DuplicatesKeep, NaN: NaNGap}},
+		{name: "invalid nan", opts: InputOptions{NaN: "zero"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := ParseInput(tt.opts, OrderSort)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, ErrInvalidConfiguration)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, input)
+		})
+	}
+}
+
+func TestInput_Prepare(t *testing.T) {
+	unsorted := func() []datapoint.TimePoint {
+		return []datapoint.TimePoint{
+			{Timestamp: time.Unix(60, 0), Value: 1},
+			{Timestamp: time.Unix(0, 0), Value: 2},
+			{Timestamp: time.Unix(60, 0), Value: 3},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		order    string
+		expected []datapoint.TimePoint
+		errIndex int
+	}{
+		{
+			name:  "sort a copy",
+			order: OrderSort,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 2},
+				{Timestamp: time.Unix(60, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: 3},
+			},
+		},
+		{
+			name:     "trust",
+			order:    OrderTrust,
+			expected: unsorted(),
+		},
+		{
+			name:     "reject",
+			order:    OrderReject,
+			errIndex: 1,
+		},
+		{
+			name:     "zero input rejects",
+			errIndex: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := unsorted()
+			result, err := Input{Order: tt.order}.Prepare(data)
+			assert.Equal(t, unsorted(), data)
+			if tt.expected == nil {
+				assert.ErrorIs(t, err, ErrUnsorted)
+				var e *Error
+				if assert.True(t, errors.As(err, &e)) {
+					assert.Equal(t, tt.errIndex, e.Index)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	// Sorted data is reduced as is
+	sorted := []datapoint.TimePoint{{Timestamp: time.Unix(0, 0)}, {Timestamp: time.Unix(60, 0)}}
+	result, err := Input{Order: OrderSort}.Prepare(sorted)
+	assert.NoError(t, err)
+	assert.Same(t, &sorted[0], &result[0])
+}
+
+func TestInput_PrepareDuplicates(t *testing.T) {
+	duplicated := func() []datapoint.TimePoint {
+		return []datapoint.TimePoint{
+			{Timestamp: time.Unix(60, 0), Value: 4},
+			{Timestamp: time.Unix(0, 0), Value: 1},
+			{Timestamp: time.Unix(60, 0), Value: 2},
+			{Timestamp: time.Unix(0, 0), Value: 3},
+			{Timestamp: time.Unix(120, 0), Value: 5},
+		}
+	}
+
+	tests := []struct {
+		duplicates string
+		expected   []datapoint.TimePoint
+	}{
+		{
+			duplicates: DuplicatesKeep,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(0, 0), Value: 3},
+				{Timestamp: time.Unix(60, 0), Value: 4},
+				{Timestamp: time.Unix(60, 0), Value: 2},
+				{Timestamp: time.Unix(120, 0), Value: 5},
+			},
+		},
+		{
+			duplicates: DuplicatesFirst,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: 4},
+				{Timestamp: time.Unix(120, 0), Value: 5},
+			},
+		},
+		{
+			duplicates: DuplicatesLast,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 3},
+				{Timestamp: time.Unix(60, 0), Value: 2},
+				{Timestamp: time.Unix(120, 0), Value: 5},
+			},
+		},
+		{
+			duplicates: DuplicatesAverage,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 2},
+				{Timestamp: time.Unix(60, 0), Value: 3},
+				{Timestamp: time.Unix(120, 0), Value: 5},
+			},
+		},
+		{
+			duplicates: DuplicatesSum,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 4},
+				{Timestamp: time.Unix(60, 0), Value: 6},
+				{Timestamp: time.Unix(120, 0), Value: 5},
+			},
+		},
+		{
+			duplicates: DuplicatesError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duplicates, func(t *testing.T) {
+			data := duplicated()
+			result, err := Input{Order: OrderSort, Duplicates: tt.duplicates}.Prepare(data)
+			assert.Equal(t, duplicated(), data)
+			if tt.expected == nil {
+				assert.ErrorIs(t, err, ErrDuplicate)
+				var e *Error
+				if assert.True(t, errors.As(err, &e)) {
+					// The index refers to data, not to its sorted copy
+					assert.Equal(t, 3, e.Index)
+					assert.Equal(t, time.Unix(0, 0), e.Timestamp)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestInput_PrepareNaN(t *testing.T) {
+	data := func() []datapoint.TimePoint {
+		return []datapoint.TimePoint{
+			{Timestamp: time.Unix(0, 0), Value: 1},
+			{Timestamp: time.Unix(60, 0), Value: math.NaN()},
+			{Timestamp: time.Unix(120, 0), Value: math.Inf(1)},
+		}
+	}
+	withoutNaN := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(120, 0), Value: math.Inf(1)},
+	}
+
+	tests := []struct {
+		name     string
+		nan      string
+		prepared []datapoint.TimePoint
+		signal   []datapoint.TimePoint
+	}{
+		{name: "skip", nan: NaNSkip, prepared: withoutNaN, signal: withoutNaN},
+		{name: "zero input skips", prepared: withoutNaN, signal: withoutNaN},
+		{name: "propagate", nan: NaNPropagate, prepared: data(), signal: data()},
+		{name: "gap", nan: NaNGap, prepared: withoutNaN, signal: data()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := data()
+			prepared, err := Input{NaN: tt.nan}.Prepare(input)
+			assert.NoError(t, err)
+			assertPoints(t, tt.prepared, prepared)
+			signal, err := Input{NaN: tt.nan}.PrepareSignal(input)
+			assert.NoError(t, err)
+			assertPoints(t, tt.signal, signal)
+			assertPoints(t, data(), input)
+		})
+	}
+
+	// Nothing is left to reduce when every value is NaN
+	_, err := Input{}.Prepare([]datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: math.NaN()}})
+	assert.ErrorIs(t, err, ErrNoData)
+}
+
+func TestInput_PrepareIndex(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(1, 0), Value: 1},
+		{Timestamp: time.Unix(1, 0), Value: 2},
+	}
+	_, err := Input{Duplicates: DuplicatesError}.Prepare(data)
+	assert.ErrorIs(t, err, ErrDuplicate)
+	var e *Error
+	if assert.True(t, errors.As(err, &e)) {
+		// The dropped NaN value still counts
+		assert.Equal(t, 2, e.Index)
+	}
+}
+
+func TestInput_Reduce(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(60, 0), Value: 1},
+		{Timestamp: time.Unix(0, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(0, 0), Value: 2},
+		{Timestamp: time.Unix(0, 0), Value: 4},
+		{Timestamp: time.Unix(30, 0), Value: 5},
+	}
+
+	result, err := Input{Order: OrderSort, Duplicates: DuplicatesAverage}.Reduce(context.Background(), passStream{}, data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 3},
+		{Timestamp: time.Unix(30, 0), Value: 5},
+		{Timestamp: time.Unix(60, 0), Value: 1},
+	}, result)
+
+	// Errors of the stream refer to data, whatever the points sorted, dropped or merged
+	_, err = Input{Order: OrderSort, Duplicates: DuplicatesAverage}.Reduce(context.Background(), &failingStream{at: 1}, data)
+	var e *Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 4, e.Index)
+		assert.Equal(t, time.Unix(30, 0), e.Timestamp)
+	}
+	_, err = Input{Order: OrderTrust, Duplicates: DuplicatesFirst}.Reduce(context.Background(), &failingStream{at: 1}, data)
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 2, e.Index)
+		assert.Equal(t, time.Unix(0, 0), e.Timestamp)
+	}
+
+	_, err = Input{}.Reduce(context.Background(), passStream{}, nil)
+	assert.ErrorIs(t, err, ErrNoData)
+	_, err = Input{}.Reduce(context.Background(), passStream{}, data[1:2])
+	assert.ErrorIs(t, err, ErrNoData)
+	_, err = Input{NaN: NaNGap}.ReduceSignal(context.Background(), passStream{}, data[1:2])
+	assert.NoError(t, err)
+}
+
+func TestInput_Stream(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(0, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(0, 0), Value: 3},
+		{Timestamp: time.Unix(60, 0), Value: math.NaN()},
+	}
+
+	tests := []struct {
+		name     string
+		nan      string
+		signal   bool
+		expected []datapoint.TimePoint
+	}{
+		{
+			name:     "skip",
+			nan:      NaNSkip,
+			expected: []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 2}},
+		},
+		{
+			name: "propagate",
+			nan:  NaNPropagate,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: math.NaN()},
+				{Timestamp: time.Unix(60, 0), Value: math.NaN()},
+			},
+		},
+		{
+			name:     "gap",
+			nan:      NaNGap,
+			expected: []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 2}},
+		},
+		{
+			name:   "gap signal",
+			nan:    NaNGap,
+			signal: true,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: math.NaN()},
+				{Timestamp: time.Unix(60, 0), Value: math.NaN()},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := Input{Duplicates: DuplicatesAverage, NaN: tt.nan}
+			stream := input.Stream(passStream{})
+			if tt.signal {
+				stream = input.StreamSignal(passStream{})
+			}
+			result, err := ReduceStream(stream, data)
+			assert.NoError(t, err)
+			assertPoints(t, tt.expected, result)
+		})
+	}
+
+	// Errors of the wrapped stream refer to the pushed points
+	stream := Input{Duplicates: DuplicatesFirst, NaN: NaNPropagate}.Stream(&failingStream{at: 1})
+	_, err := ReduceStream(stream, data)
+	var e *Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 3, e.Index)
+	}
+	stream = Input{NaN: NaNPropagate}.Stream(&failingStream{at: 1})
+	_, err = ReduceStream(stream, data)
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 1, e.Index)
+	}
+}
+
+// failingStream fails on the point pushed at index at.
+type failingStream struct {
+	at    int
+	index int
+}
+
+func (s *failingStream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	index := s.index
+	s.index++
+	if index == s.at {
+		return nil, &Error{Index: index, Timestamp: point.Timestamp, Err: ErrUnsorted}
+	}
+	return []datapoint.TimePoint{point}, nil
+}
+
+func (s *failingStream) Flush() ([]datapoint.TimePoint, error) {
+	return nil, nil
+}
+
+// assertPoints asserts that two series are equal, NaN values included.
+func assertPoints(t *testing.T, expected, actual []datapoint.TimePoint) {
+	t.Helper()
+	if !assert.Len(t, actual, len(expected)) {
+		return
+	}
+	for i := range expected {
+		assert.Equal(t, expected[i].Timestamp, actual[i].Timestamp)
+		if math.IsNaN(expected[i].Value) {
+			assert.True(t, math.IsNaN(actual[i].Value), "value %d should be NaN", i)
+		} else {
+			assert.Equal(t, expected[i].Value, actual[i].Value)
+		}
+	}
+}
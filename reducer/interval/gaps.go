@@ -0,0 +1,69 @@
+package interval
+
+import (
+	"math"
+	"time"
+
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+const (
+	// GapsSkip emits nothing for the empty intervals.
+	GapsSkip = "skip"
+	// GapsNaN emits NaN for the empty intervals.
+	GapsNaN = "nan"
+	// GapsZero emits zero for the empty intervals.
+	GapsZero = "zero"
+	// GapsPrevious carries the value of the previous interval over the empty intervals.
+	GapsPrevious = "previous"
+	// GapsLinear interpolates linearly between the intervals around the empty intervals.
+	GapsLinear = "linear"
+)
+
+// Emitter stamps the values reduced over successive intervals and fills the
+// empty intervals between them according to the gaps policy of the Spec.
+// Empty intervals before the first value and after the last one are never filled.
+type Emitter struct {
+	spec     Spec
+	started  bool
+	previous datapoint.TimePoint // Value of the previous interval, stamped with its start
+}
+
+// NewEmitter returns an Emitter for the intervals described by spec.
+func NewEmitter(spec Spec) *Emitter {
+	return &Emitter{spec: spec}
+}
+
+// Emit returns the points of the empty intervals since the previous call, if
+// they are filled, followed by the point of the interval starting at start.
+// Intervals must be emitted in chronological order.
+func (e *Emitter) Emit(start time.Time, value float64) []datapoint.TimePoint {
+	var reduced []datapoint.TimePoint
+	if e.started && e.spec.gaps != GapsSkip {
+		for t := e.spec.Next(e.previous.Timestamp); t.Before(start); t = e.spec.Next(t) {
+			reduced = append(reduced, datapoint.TimePoint{
+				Timestamp: e.spec.Label(t),
+				Value:     e.fill(t, start, value),
+			})
+		}
+	}
+	e.started = true
+	e.previous = datapoint.TimePoint{Timestamp: start, Value: value}
+	return append(reduced, datapoint.TimePoint{Timestamp: e.spec.Label(start), Value: value})
+}
+
+// fill returns the value of the empty interval starting at t, the next interval
+// holding data starting at next with the given value.
+func (e *Emitter) fill(t, next time.Time, value float64) float64 {
+	switch e.spec.gaps {
+	case GapsNaN:
+		return math.NaN()
+	case GapsPrevious:
+		return e.previous.Value
+	case GapsLinear:
+		ratio := float64(t.Sub(e.previous.Timestamp)) / float64(next.Sub(e.previous.Timestamp))
+		return e.previous.Value + ratio*(value-e.previous.Value)
+	default:
+		return 0
+	}
+}
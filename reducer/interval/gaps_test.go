@@ -0,0 +1,63 @@
+package interval
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		gaps     string
+		expected []float64
+	}{
+		{name: "skip", gaps: GapsSkip, expected: []float64{1, 7}},
+		{name: "nan", gaps: GapsNaN, expected: []float64{1, math.NaN(), math.NaN(), 7}},
+		{name: "zero", gaps: GapsZero, expected: []float64{1, 0, 0, 7}},
+		{name: "previous", gaps: GapsPrevious, expected: []float64{1, 1, 1, 7}},
+		{name: "linear", gaps: GapsLinear, expected: []float64{1, 3, 5, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Parse("1m", Options{Gaps: tt.gaps})
+			assert.NoError(t, err)
+			e := NewEmitter(spec)
+
+			reduced := e.Emit(time.Unix(0, 0), 1)
+			reduced = append(reduced, e.Emit(time.Unix(180, 0), 7)...)
+
+			assert.Len(t, reduced, len(tt.expected))
+			for i, point := range reduced {
+				if math.IsNaN(tt.expected[i]) {
+					assert.True(t, math.IsNaN(point.Value))
+				} else {
+					assert.Equal(t, tt.expected[i], point.Value)
+				}
+			}
+			assert.Equal(t, time.Unix(0, 0), reduced[0].Timestamp)
+			assert.Equal(t, time.Unix(180, 0), reduced[len(reduced)-1].Timestamp)
+		})
+	}
+
+	_, err := Parse("1m", Options{Gaps: "invalid"})
+	assert.Error(t, err)
+}
+
+func TestEmitter_Label(t *testing.T) {
+	spec, err := Parse("1m", Options{Gaps: GapsZero, Label: LabelEnd})
+	assert.NoError(t, err)
+	e := NewEmitter(spec)
+
+	reduced := e.Emit(time.Unix(0, 0), 1)
+	reduced = append(reduced, e.Emit(time.Unix(120, 0), 2)...)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Unix(60, 0), Value: 1},
+		{Timestamp: time.Unix(120, 0), Value: 0},
+		{Timestamp: time.Unix(180, 0), Value: 2},
+	}, reduced)
+}
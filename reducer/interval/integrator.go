@@ -0,0 +1,115 @@
+package interval
+
+import (
+	"math"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// Integral is the integral of a signal over an interval.
+type Integral struct {
+	Start   time.Time     // Start of the interval
+	Area    float64       // Integral of the signal over the covered part of the interval, in value × nanoseconds
+	Covered time.Duration // Part of the interval covered by the signal
+}
+
+// Integrator integrates a signal defined by its samples over the successive
+// intervals described by a Spec, the first interval being the one given by
+// Spec.Start for the first sample. It is the building block of the reducers
+// treating data as a continuous signal, such as time-weighted averages or
+// energy.
+type Integrator struct {
+	spec      Spec
+	linear    bool
+	gaps      bool
+	started   bool
+	previous  datapoint.TimePoint
+	startTime time.Time
+	area      float64
+	covered   time.Duration
+	index     int // Index of the next pushed point
+}
+
+// NewIntegrator returns an Integrator over the intervals described by spec.
+// With linear, the signal is interpolated linearly between two consecutive
+// samples, otherwise each value is held until the next sample. With gaps, the
+// signal is undefined on the segments going to or from a NaN value, which are
+// left out of the integrals.
+func NewIntegrator(spec Spec, linear, gaps bool) *Integrator {
+	return &Integrator{spec: spec, linear: linear, gaps: gaps}
+}
+
+// Push integrates the segment between the previous point and this one and
+// returns the integrals of the intervals it closes.
+// Pushing a point older than the previous one returns an error, as does pushing
+// any point when the Spec of the Integrator is not valid.
+func (in *Integrator) Push(point datapoint.TimePoint) ([]Integral, error) {
+	index := in.index
+	in.index++
+	if !in.started {
+		if err := in.spec.Validate(); err != nil {
+			return nil, err
+		}
+		in.started = true
+		in.startTime = in.spec.Start(point.Timestamp)
+		in.previous = point
+		return nil, nil
+	}
+	if point.Timestamp.Before(in.previous.Timestamp) {
+		return nil, &reducer.Error{Index: index, Timestamp: point.Timestamp, Err: reducer.ErrUnsorted}
+	}
+
+	var integrals []Integral
+	from := in.previous.Timestamp
+	for end := in.spec.Next(in.startTime); !point.Timestamp.Before(end); end = in.spec.Next(in.startTime) {
+		in.integrate(from, end, point)
+		integrals = append(integrals, Integral{Start: in.startTime, Area: in.area, Covered: in.covered})
+		in.startTime = end
+		in.area = 0
+		in.covered = 0
+		from = end
+	}
+	in.integrate(from, point.Timestamp, point)
+	in.previous = point
+	return integrals, nil
+}
+
+// Flush returns the integral of the last interval and the last pushed point,
+// and false when no point was pushed.
+func (in *Integrator) Flush() (Integral, datapoint.TimePoint, bool) {
+	if !in.started {
+		return Integral{}, datapoint.TimePoint{}, false
+	}
+	in.started = false
+	return Integral{Start: in.startTime, Area: in.area, Covered: in.covered}, in.previous, true
+}
+
+// integrate adds the area of the signal between from and to, both lying on the
+// segment going from the previous point to next.
+func (in *Integrator) integrate(from, to time.Time, next datapoint.TimePoint) {
+	width := to.Sub(from)
+	if width <= 0 {
+		return
+	}
+	if in.gaps && (math.IsNaN(in.previous.Value) || in.linear && math.IsNaN(next.Value)) {
+		return
+	}
+	if in.linear {
+		in.area += (in.valueAt(from, next) + in.valueAt(to, next)) / 2 * float64(width)
+	} else {
+		in.area += in.previous.Value * float64(width)
+	}
+	in.covered += width
+}
+
+// valueAt linearly interpolates the signal at t between the previous point and next.
+func (in *Integrator) valueAt(t time.Time, next datapoint.TimePoint) float64 {
+	span := next.Timestamp.Sub(in.previous.Timestamp)
+	if span <= 0 {
+		return next.Value
+	}
+	ratio := float64(t.Sub(in.previous.Timestamp)) / float64(span)
+	return in.previous.Value + (next.Value-in.previous.Value)*ratio
+}
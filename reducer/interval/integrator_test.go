@@ -0,0 +1,74 @@
+package interval
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntegrator(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 0},
+		{Timestamp: time.Unix(30, 0), Value: 10},
+		{Timestamp: time.Unix(45, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(90, 0), Value: 20},
+	}
+	tests := []struct {
+		name     string
+		linear   bool
+		gaps     bool
+		expected []Integral
+	}{
+		{
+			name:   "step",
+			linear: false,
+			gaps:   true,
+			expected: []Integral{
+				// The NaN value held from 45s on is left out
+				{Start: time.Unix(0, 0), Area: 10 * float64(15*time.Second), Covered: 45 * time.Second},
+				{Start: time.Unix(60, 0), Area: 0, Covered: 0},
+			},
+		},
+		{
+			name:   "linear",
+			linear: true,
+			gaps:   true,
+			expected: []Integral{
+				{Start: time.Unix(0, 0), Area: 5 * float64(30*time.Second), Covered: 30 * time.Second},
+				{Start: time.Unix(60, 0), Area: 0, Covered: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := NewIntegrator(Fixed(time.Minute), tt.linear, tt.gaps)
+			var integrals []Integral
+			for _, point := range data {
+				out, err := in.Push(point)
+				assert.NoError(t, err)
+				integrals = append(integrals, out...)
+			}
+			integral, last, ok := in.Flush()
+			assert.True(t, ok)
+			assert.Equal(t, data[len(data)-1], last)
+			integrals = append(integrals, integral)
+			assert.Equal(t, tt.expected, integrals)
+		})
+	}
+}
+
+func TestIntegrator_Unsorted(t *testing.T) {
+	in := NewIntegrator(Fixed(time.Minute), true, false)
+	_, err := in.Push(datapoint.TimePoint{Timestamp: time.Unix(30, 0), Value: 1})
+	assert.NoError(t, err)
+	_, err = in.Push(datapoint.TimePoint{Timestamp: time.Unix(0, 0), Value: 1})
+	assert.ErrorIs(t, err, reducer.ErrUnsorted)
+
+	_, _, ok := NewIntegrator(Fixed(time.Minute), true, false).Flush()
+	assert.False(t, ok, "nothing to flush without points")
+}
@@ -0,0 +1,82 @@
+package interval
+
+import (
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// Aggregator accumulates the points falling into a single interval.
+type Aggregator interface {
+	// Add adds a point to the current interval.
+	Add(point datapoint.TimePoint)
+	// Value returns the aggregated value of the current interval and resets the aggregator.
+	// It is only called for intervals holding at least one point.
+	Value() float64
+}
+
+// NewStream returns a StreamReducer aggregating the pushed points over the
+// intervals described by spec, the first interval being the one given by
+// spec.Start for the first point. Each interval holding points is reduced to a
+// single point stamped with spec.Label of the interval, and the empty intervals
+// are emitted according to the gaps policy of spec (skipped by default).
+// Pushing a point older than the previous one returns an error, as does pushing
+// any point when spec is not valid.
+func NewStream(spec Spec, aggregator Aggregator) reducer.StreamReducer {
+	return &stream{spec: spec, aggregator: aggregator, emitter: NewEmitter(spec)}
+}
+
+// stream tracks the interval currently being aggregated.
+type stream struct {
+	spec       Spec
+	aggregator Aggregator
+	emitter    *Emitter
+	started    bool
+	startTime  time.Time
+	endTime    time.Time
+	previous   time.Time
+	count      int
+	index      int // Index of the next pushed point
+}
+
+func (s *stream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	index := s.index
+	s.index++
+	if !s.started {
+		if err := s.spec.Validate(); err != nil {
+			return nil, err
+		}
+		s.started = true
+		s.startTime = s.spec.Start(point.Timestamp)
+		s.endTime = s.spec.Next(s.startTime)
+	} else if point.Timestamp.Before(s.previous) {
+		return nil, &reducer.Error{Index: index, Timestamp: point.Timestamp, Err: reducer.ErrUnsorted}
+	}
+	s.previous = point.Timestamp
+
+	var reduced []datapoint.TimePoint
+	if !point.Timestamp.Before(s.endTime) {
+		reduced = s.emit()
+		// Skip the empty intervals up to the one holding the point
+		s.startTime = s.spec.Advance(s.startTime, point.Timestamp)
+		s.endTime = s.spec.Next(s.startTime)
+	}
+	s.aggregator.Add(point)
+	s.count++
+	return reduced, nil
+}
+
+func (s *stream) Flush() ([]datapoint.TimePoint, error) {
+	s.started = false
+	return s.emit(), nil
+}
+
+// emit returns the aggregated point of the current interval, if it holds any point.
+func (s *stream) emit() []datapoint.TimePoint {
+	if s.count == 0 {
+		return nil
+	}
+	s.count = 0
+	return s.emitter.Emit(s.startTime, s.aggregator.Value())
+}
@@ -0,0 +1,106 @@
+package interval
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+// countAggregator counts the points of an interval.
+type countAggregator struct {
+	count float64
+}
+
+func (a *countAggregator) Add(datapoint.TimePoint) {
+	a.count++
+}
+
+func (a *countAggregator) Value() float64 {
+	value := a.count
+	a.count = 0
+	return value
+}
+
+func TestNewStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		expectErr bool
+	}{
+		{
+			name:     "no data",
+			data:     []datapoint.TimePoint{},
+			expected: nil,
+		},
+		{
+			name: "intervals anchored at the first point",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(10, 0), Value: 1},
+				{Timestamp: time.Unix(40, 0), Value: 1},
+				{Timestamp: time.Unix(70, 0), Value: 1},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(10, 0), Value: 2},
+				{Timestamp: time.Unix(70, 0), Value: 1},
+			},
+		},
+		{
+			name: "empty intervals are skipped",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(150, 0), Value: 1},
+				{Timestamp: time.Unix(170, 0), Value: 1},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(120, 0), Value: 2},
+			},
+		},
+		{
+			name: "unsorted data",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(30, 0), Value: 1},
+				{Timestamp: time.Unix(0, 0), Value: 1},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := reducer.ReduceStream(NewStream(Fixed(time.Minute), &countAggregator{}), tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestNewStream_Unsorted(t *testing.T) {
+	_, err := reducer.ReduceStream(NewStream(Fixed(time.Minute), &countAggregator{}), []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(30, 0), Value: 1},
+		{Timestamp: time.Unix(20, 0), Value: 1},
+	})
+	assert.ErrorIs(t, err, reducer.ErrUnsorted)
+	var e *reducer.Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 2, e.Index)
+		assert.Equal(t, time.Unix(20, 0), e.Timestamp)
+	}
+}
+
+func TestNewStream_InvalidSpec(t *testing.T) {
+	_, err := reducer.ReduceStream(NewStream(Spec{}, &countAggregator{}), []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+	})
+	assert.ErrorIs(t, err, reducer.ErrInvalidInterval)
+}
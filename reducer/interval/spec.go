@@ -0,0 +1,393 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+
+	// Embed the IANA time zone database so that time zones can be loaded on
+	// hosts without one.
+	_ "time/tzdata"
+)
+
+const (
+	// AlignFirst starts the first fixed interval at the first point.
+	AlignFirst = "first"
+	// AlignEpoch aligns fixed intervals on the Unix epoch, so 15 minute
+	// intervals start at :00, :15, :30 and :45.
+	AlignEpoch = "epoch"
+	// AlignOrigin aligns fixed intervals on the configured origin.
+	AlignOrigin = "origin"
+
+	// LabelStart stamps the point reduced over an interval with its start.
+	LabelStart = "start"
+	// LabelEnd stamps the point reduced over an interval with its end.
+	LabelEnd = "end"
+	// LabelCenter stamps the point reduced over an interval with its middle.
+	LabelCenter = "center"
+)
+
+// Options holds the interval settings shared by the configurations of the
+// interval reducers, next to their interval string.
+type Options struct {
+	// Timezone is the IANA name of the time zone calendar intervals are laid
+	// out in, such as "Europe/Paris". Defaults to UTC.
+	Timezone string `json:"timezone" default:"UTC" description:"IANA time zone calendar intervals are laid out in"`
+	// Align is how fixed intervals are laid out: "first" (default), "epoch" or
+	// "origin". It defaults to "origin" when an origin is set. Calendar
+	// intervals are always aligned on the calendar.
+	Align string `json:"align" enum:"first,epoch,origin" description:"Alignment of fixed intervals: on the first point, the Unix epoch or the origin"`
+	// Origin is the RFC3339 time fixed intervals are aligned on with "origin".
+	Origin string `json:"origin" description:"RFC 3339 time fixed intervals are aligned on"`
+	// Offset is a duration shifting aligned intervals, such as "6h" for days
+	// starting at 6 AM. Calendar intervals are shifted on the wall clock.
+	Offset string `json:"offset" description:"Duration shifting aligned intervals"`
+	// Label is the time the point reduced over an interval is stamped with:
+	// "start" (default), "end" or "center" of the interval. Reducers keeping
+	// the original timestamps of the points, such as M4, reject other labels.
+	Label string `json:"label" default:"start" enum:"start,end,center" description:"Time the point of an interval is stamped with"`
+	// Gaps is how the empty intervals between two intervals holding data are
+	// emitted: "skip" (default), "nan", "zero", "previous" or "linear".
+	// Reducers integrating the signal over time never have empty intervals
+	// and reject other policies.
+	Gaps string `json:"gaps" enum:"skip,nan,zero,previous,linear" description:"Value of the empty intervals between intervals holding data"`
+}
+
+// RejectGaps returns an error matching reducer.ErrInvalidConfiguration if o sets
+// a gaps policy other than GapsSkip, for the reducers without empty intervals to fill.
+func (o Options) RejectGaps() error {
+	if o.Gaps != "" && o.Gaps != GapsSkip {
+		return fmt.Errorf("%w: gaps not supported, got %q", reducer.ErrInvalidConfiguration, o.Gaps)
+	}
+	return nil
+}
+
+// RejectLabel returns an error matching reducer.ErrInvalidConfiguration if o
+// sets a label other than LabelStart, for the reducers not stamping their
+// points with the intervals they belong to.
+func (o Options) RejectLabel() error {
+	if o.Label != "" && o.Label != LabelStart {
+		return fmt.Errorf("%w: label not supported, got %q", reducer.ErrInvalidConfiguration, o.Label)
+	}
+	return nil
+}
+
+// unit is a calendar unit, whose duration depends on the date and time zone.
+type unit int
+
+const (
+	day unit = iota + 1
+	week
+	month
+	year
+)
+
+// calendarPattern matches the calendar intervals, such as "1d", "2 weeks" or "1 month".
+var calendarPattern = regexp.MustCompile(`^(\d*)\s*([a-z]+)$`)
+
+var calendarUnits = map[string]unit{
+	"d": day, "day": day, "days": day,
+	"w": week, "week": week, "weeks": week,
+	"mo": month, "month": month, "months": month,
+	"y": year, "year": year, "years": year,
+}
+
+// Spec describes the length of an interval: either a fixed duration, or a
+// number of calendar days, weeks, months or years in a time zone. Calendar
+// intervals follow the wall clock, so a day lasts 23 or 25 hours on daylight
+// saving time changes, and they are aligned on the calendar: days start at
+// midnight, weeks on Monday, months on the first day and years on January 1st.
+//
+// Fixed intervals start at the first point of the series unless they are
+// aligned on the Unix epoch or on an origin, so that the intervals of series
+// starting at different times line up.
+type Spec struct {
+	duration time.Duration
+	unit     unit
+	count    int
+	location *time.Location
+	aligned  bool
+	origin   time.Time     // Start of an interval when aligned
+	offset   time.Duration // Wall clock shift of calendar intervals
+	label    string
+	gaps     string
+}
+
+// Fixed returns the Spec of intervals lasting d with the default options, as
+// returned by Parse for a duration.
+func Fixed(d time.Duration) Spec {
+	return Spec{duration: d, location: time.UTC, label: LabelStart, gaps: GapsSkip}
+}
+
+// Parse parses an interval string, either a Go duration such as "15m", a number
+// of seconds such as "900", or a calendar interval such as "1d", "1w", "3mo" or
+// "1y", along with its options.
+//
+// Parameters:
+//   - interval: The interval string.
+//   - opts: The options of the interval, such as its time zone.
+//
+// Returns:
+//   - Spec: The parsed interval.
+//   - error: An error if the interval is invalid or not positive, or if any of its options is invalid.
+func Parse(interval string, opts Options) (Spec, error) {
+	location := time.UTC
+	if opts.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(opts.Timezone)
+		if err != nil {
+			return Spec{}, fmt.Errorf("%w: invalid timezone: %w", reducer.ErrInvalidConfiguration, err)
+		}
+	}
+
+	label := opts.Label
+	switch label {
+	case "":
+		label = LabelStart
+	case LabelStart, LabelEnd, LabelCenter:
+	default:
+		return Spec{}, fmt.Errorf("%w: invalid label: %q", reducer.ErrInvalidConfiguration, opts.Label)
+	}
+
+	gaps := opts.Gaps
+	switch gaps {
+	case "":
+		gaps = GapsSkip
+	case GapsSkip, GapsNaN, GapsZero, GapsPrevious, GapsLinear:
+	default:
+		return Spec{}, fmt.Errorf("%w: invalid gaps: %q", reducer.ErrInvalidConfiguration, opts.Gaps)
+	}
+
+	var offset time.Duration
+	if opts.Offset != "" {
+		var err error
+		offset, err = time.ParseDuration(opts.Offset)
+		if err != nil {
+			return Spec{}, fmt.Errorf("%w: invalid offset: %w", reducer.ErrInvalidConfiguration, err)
+		}
+	}
+
+	align := opts.Align
+	if align == "" {
+		align = AlignFirst
+		if opts.Origin != "" {
+			align = AlignOrigin
+		}
+	}
+	var origin time.Time
+	switch align {
+	case AlignFirst:
+		if opts.Origin != "" {
+			return Spec{}, fmt.Errorf("%w: origin requires %q alignment", reducer.ErrInvalidConfiguration, AlignOrigin)
+		}
+	case AlignEpoch:
+		if opts.Origin != "" {
+			return Spec{}, fmt.Errorf("%w: origin requires %q alignment", reducer.ErrInvalidConfiguration, AlignOrigin)
+		}
+		origin = time.Unix(0, 0)
+	case AlignOrigin:
+		if opts.Origin == "" {
+			return Spec{}, fmt.Errorf("%w: origin alignment requires an origin", reducer.ErrInvalidConfiguration)
+		}
+		var err error
+		origin, err = time.Parse(time.RFC3339, opts.Origin)
+		if err != nil {
+			return Spec{}, fmt.Errorf("%w: invalid origin: %w", reducer.ErrInvalidConfiguration, err)
+		}
+	default:
+		return Spec{}, fmt.Errorf("%w: invalid align: %q", reducer.ErrInvalidConfiguration, opts.Align)
+	}
+
+	if match := calendarPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(interval))); match != nil {
+		if u, ok := calendarUnits[match[2]]; ok {
+			count := 1
+			if match[1] != "" {
+				var err error
+				count, err = strconv.Atoi(match[1])
+				if err != nil {
+					return Spec{}, fmt.Errorf("%w: %w", reducer.ErrInvalidInterval, err)
+				}
+			}
+			if count <= 0 {
+				return Spec{}, fmt.Errorf("%w: must be positive, got %q", reducer.ErrInvalidInterval, interval)
+			}
+			if align == AlignOrigin {
+				return Spec{}, fmt.Errorf("%w: calendar intervals cannot be aligned on an origin", reducer.ErrInvalidConfiguration)
+			}
+			return Spec{unit: u, count: count, location: location, offset: offset, label: label, gaps: gaps}, nil
+		}
+	}
+
+	d, err := parseDuration(interval)
+	if err != nil {
+		return Spec{}, fmt.Errorf("%w: %w", reducer.ErrInvalidInterval, err)
+	}
+	if d <= 0 {
+		return Spec{}, fmt.Errorf("%w: must be positive, got %v", reducer.ErrInvalidInterval, d)
+	}
+	if align == AlignFirst {
+		if offset != 0 {
+			return Spec{}, fmt.Errorf("%w: offset requires aligned intervals", reducer.ErrInvalidConfiguration)
+		}
+		return Spec{duration: d, location: location, label: label, gaps: gaps}, nil
+	}
+	return Spec{duration: d, location: location, aligned: true, origin: origin.Add(offset), label: label, gaps: gaps}, nil
+}
+
+// parseDuration parses a Go duration, or a bare number of seconds.
+func parseDuration(s string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && !math.IsNaN(seconds) && !math.IsInf(seconds, 0) {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// IsZero reports whether s is the zero Spec, which describes no interval.
+func (s Spec) IsZero() bool {
+	return s == Spec{}
+}
+
+// Validate returns an error matching reducer.ErrInvalidInterval if s does not
+// describe intervals of a positive length, such as the zero Spec or Fixed(0).
+func (s Spec) Validate() error {
+	if s.IsCalendar() && s.count > 0 || !s.IsCalendar() && s.duration > 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: must be positive, got %v", reducer.ErrInvalidInterval, s)
+}
+
+// Duration returns the length of fixed intervals, and zero for calendar intervals.
+func (s Spec) Duration() time.Duration {
+	return s.duration
+}
+
+// IsCalendar reports whether the intervals follow the calendar rather than a fixed duration.
+func (s Spec) IsCalendar() bool {
+	return s.unit != 0
+}
+
+// String returns the interval as a string.
+func (s Spec) String() string {
+	if !s.IsCalendar() {
+		return s.duration.String()
+	}
+	names := map[unit]string{day: "d", week: "w", month: "mo", year: "y"}
+	return fmt.Sprintf("%d%s %s", s.count, names[s.unit], s.location)
+}
+
+// Start returns the start of the first interval of a series whose first point
+// is at first. Fixed intervals start at the first point unless they are
+// aligned, other intervals at the start of the aligned interval holding it.
+func (s Spec) Start(first time.Time) time.Time {
+	if s.IsCalendar() {
+		return s.floor(first)
+	}
+	if !s.aligned {
+		return first
+	}
+	n := first.Sub(s.origin) / s.duration
+	if first.Before(s.origin.Add(n * s.duration)) {
+		n--
+	}
+	return s.origin.Add(n * s.duration).In(first.Location())
+}
+
+// Next returns the start of the interval following the one starting at start.
+func (s Spec) Next(start time.Time) time.Time {
+	if !s.IsCalendar() {
+		return start.Add(s.duration)
+	}
+	y, m, d := s.date(start)
+	switch s.unit {
+	case day:
+		d += s.count
+	case week:
+		d += 7 * s.count
+	case month:
+		m += time.Month(s.count)
+	case year:
+		y += s.count
+	}
+	return s.at(y, m, d)
+}
+
+// Advance returns the start of the interval holding t, intervals being laid out
+// from start. t must not be before start.
+func (s Spec) Advance(start, t time.Time) time.Time {
+	if !s.IsCalendar() {
+		return start.Add(t.Sub(start) / s.duration * s.duration)
+	}
+	return s.floor(t)
+}
+
+// Label returns the time the point reduced over the interval starting at start
+// is stamped with, as configured by the Label option.
+func (s Spec) Label(start time.Time) time.Time {
+	switch s.label {
+	case LabelEnd:
+		return s.Next(start)
+	case LabelCenter:
+		return start.Add(s.Next(start).Sub(start) / 2)
+	default:
+		return start
+	}
+}
+
+// floor returns the start of the calendar interval holding t. Intervals of
+// several units are counted from the Unix epoch, so "3mo" yields quarters.
+func (s Spec) floor(t time.Time) time.Time {
+	y, m, d := s.date(t)
+	n := int64(s.count)
+	switch s.unit {
+	case day:
+		days := civilDays(y, m, d)
+		return s.at(civilDate(days - mod(days, n)))
+	case week:
+		// 1970-01-01 was a Thursday, weeks are counted from Monday 1969-12-29
+		days := civilDays(y, m, d) + 3
+		weeks := (days - mod(days, 7)) / 7
+		return s.at(civilDate((weeks-mod(weeks, n))*7 - 3))
+	case month:
+		months := int64(y)*12 + int64(m) - 1 - 1970*12
+		months -= mod(months, n)
+		return s.at(1970, time.Month(months+1), 1)
+	default:
+		years := int64(y) - 1970
+		return s.at(1970+int(years-mod(years, n)), time.January, 1)
+	}
+}
+
+// date returns the calendar date t belongs to, once the offset is removed from
+// its wall clock.
+func (s Spec) date(t time.Time) (int, time.Month, int) {
+	t = t.In(s.location)
+	if s.offset != 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond()-int(s.offset), time.UTC)
+	}
+	return t.Date()
+}
+
+// at returns the start of the calendar date: midnight shifted by the offset.
+func (s Spec) at(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, int(s.offset), s.location)
+}
+
+// civilDays returns the number of days between 1970-01-01 and the given date.
+func civilDays(y int, m time.Month, d int) int64 {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// civilDate returns the date lying days after 1970-01-01.
+func civilDate(days int64) (int, time.Month, int) {
+	return time.Unix(days*86400, 0).UTC().Date()
+}
+
+// mod returns the non-negative remainder of a divided by n.
+func mod(a, n int64) int64 {
+	return ((a % n) + n) % n
+}
@@ -0,0 +1,289 @@
+package interval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  string
+		opts      Options
+		calendar  bool
+		expectErr bool
+	}{
+		{name: "duration", interval: "15m"},
+		{name: "seconds", interval: "900"},
+		{name: "zero seconds", interval: "0", expectErr: true},
+		{name: "infinite seconds", interval: "Inf", expectErr: true},
+		{name: "day", interval: "1d", calendar: true},
+		{name: "days with space", interval: "2 days", calendar: true},
+		{name: "month", interval: "1 month", calendar: true},
+		{name: "quarter", interval: "3mo", calendar: true},
+		{name: "year with time zone", interval: "1y", opts: Options{Timezone: "Europe/Paris"}, calendar: true},
+		{name: "invalid interval", interval: "invalid", expectErr: true},
+		{name: "zero calendar interval", interval: "0d", expectErr: true},
+		{name: "negative duration", interval: "-1m", expectErr: true},
+		{name: "unknown time zone", interval: "1d", opts: Options{Timezone: "Mars/Olympus"}, expectErr: true},
+		{name: "epoch alignment", interval: "15m", opts: Options{Align: AlignEpoch, Offset: "5m"}},
+		{name: "origin alignment", interval: "15m", opts: Options{Origin: "2023-10-01T00:05:00Z"}},
+		{name: "calendar offset", interval: "1d", opts: Options{Offset: "6h"}, calendar: true},
+		{name: "unknown alignment", interval: "15m", opts: Options{Align: "middle"}, expectErr: true},
+		{name: "missing origin", interval: "15m", opts: Options{Align: AlignOrigin}, expectErr: true},
+		{name: "invalid origin", interval: "15m", opts: Options{Origin: "yesterday"}, expectErr: true},
+		{name: "origin with epoch alignment", interval: "15m", opts: Options{Align: AlignEpoch, Origin: "2023-10-01T00:05:00Z"}, expectErr: true},
+		{name: "calendar origin", interval: "1d", opts: Options{Origin: "2023-10-01T00:05:00Z"}, expectErr: true},
+		{name: "offset without alignment", interval: "15m", opts: Options{Offset: "5m"}, expectErr: true},
+		{name: "invalid offset", interval: "15m", opts: Options{Align: AlignEpoch, Offset: "soon"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Parse(tt.interval, tt.opts)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, reducer.ErrInvalidConfiguration)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.calendar, spec.IsCalendar())
+		})
+	}
+}
+
+func TestSpec_DaylightSavingTime(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NoError(t, err)
+	spec, err := Parse("1d", Options{Timezone: "Europe/Paris"})
+	assert.NoError(t, err)
+
+	start := spec.Start(time.Date(2023, 3, 26, 10, 0, 0, 0, paris))
+	assert.Equal(t, time.Date(2023, 3, 26, 0, 0, 0, 0, paris), start)
+	assert.Equal(t, 23*time.Hour, spec.Next(start).Sub(start))
+
+	start = spec.Start(time.Date(2023, 10, 29, 23, 59, 0, 0, paris))
+	assert.Equal(t, time.Date(2023, 10, 29, 0, 0, 0, 0, paris), start)
+	assert.Equal(t, 25*time.Hour, spec.Next(start).Sub(start))
+}
+
+func TestSpec_Calendar(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval string
+		t        time.Time
+		start    time.Time
+		next     time.Time
+	}{
+		{
+			name:     "week starts on Monday",
+			interval: "1w",
+			t:        time.Date(2023, 10, 5, 12, 0, 0, 0, time.UTC),
+			start:    time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2023, 10, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "month",
+			interval: "1mo",
+			t:        time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+			start:    time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "quarter",
+			interval: "3mo",
+			t:        time.Date(2023, 8, 15, 0, 0, 0, 0, time.UTC),
+			start:    time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "year",
+			interval: "1y",
+			t:        time.Date(2023, 8, 15, 0, 0, 0, 0, time.UTC),
+			start:    time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Parse(tt.interval, Options{})
+			assert.NoError(t, err)
+			start := spec.Start(tt.t)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.next, spec.Next(start))
+			assert.Equal(t, tt.start, spec.Advance(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), tt.t))
+		})
+	}
+}
+
+func TestParse_Seconds(t *testing.T) {
+	spec, err := Parse("900", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, Fixed(15*time.Minute), spec)
+
+	spec, err = Parse("0.5", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, Fixed(500*time.Millisecond), spec)
+}
+
+func TestSpec_Fixed(t *testing.T) {
+	spec := Fixed(15 * time.Minute)
+	first := time.Date(2023, 10, 1, 10, 3, 17, 0, time.UTC)
+	assert.Equal(t, first, spec.Start(first))
+	assert.Equal(t, first.Add(15*time.Minute), spec.Next(first))
+	assert.Equal(t, first.Add(45*time.Minute), spec.Advance(first, first.Add(50*time.Minute)))
+}
+
+func TestSpec_Align(t *testing.T) {
+	first := time.Date(2023, 10, 1, 10, 3, 17, 0, time.UTC)
+	tests := []struct {
+		name     string
+		interval string
+		opts     Options
+		first    time.Time
+		start    time.Time
+	}{
+		{
+			name:     "first point",
+			interval: "15m",
+			first:    first,
+			start:    first,
+		},
+		{
+			name:     "epoch",
+			interval: "15m",
+			opts:     Options{Align: AlignEpoch},
+			first:    first,
+			start:    time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "epoch with offset",
+			interval: "15m",
+			opts:     Options{Align: AlignEpoch, Offset: "5m"},
+			first:    first,
+			start:    time.Date(2023, 10, 1, 9, 50, 0, 0, time.UTC),
+		},
+		{
+			name:     "epoch before 1970",
+			interval: "1h",
+			opts:     Options{Align: AlignEpoch},
+			first:    time.Date(1969, 12, 31, 22, 30, 0, 0, time.UTC),
+			start:    time.Date(1969, 12, 31, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "origin",
+			interval: "1h",
+			opts:     Options{Origin: "2023-01-01T00:20:00Z"},
+			first:    first,
+			start:    time.Date(2023, 10, 1, 9, 20, 0, 0, time.UTC),
+		},
+		{
+			name:     "origin after the first point",
+			interval: "1h",
+			opts:     Options{Origin: "2024-01-01T00:20:00Z"},
+			first:    first,
+			start:    time.Date(2023, 10, 1, 9, 20, 0, 0, time.UTC),
+		},
+		{
+			name:     "calendar day with offset",
+			interval: "1d",
+			opts:     Options{Offset: "6h"},
+			first:    time.Date(2023, 10, 1, 3, 0, 0, 0, time.UTC),
+			start:    time.Date(2023, 9, 30, 6, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Parse(tt.interval, tt.opts)
+			assert.NoError(t, err)
+			assert.True(t, tt.start.Equal(spec.Start(tt.first)), "got %v", spec.Start(tt.first))
+		})
+	}
+}
+
+func TestSpec_OffsetDaylightSavingTime(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NoError(t, err)
+	spec, err := Parse("1d", Options{Timezone: "Europe/Paris", Offset: "6h"})
+	assert.NoError(t, err)
+
+	start := spec.Start(time.Date(2023, 3, 26, 12, 0, 0, 0, paris))
+	assert.Equal(t, time.Date(2023, 3, 26, 6, 0, 0, 0, paris), start)
+	assert.Equal(t, time.Date(2023, 3, 27, 6, 0, 0, 0, paris), spec.Next(start))
+	assert.Equal(t, 24*time.Hour, spec.Next(start).Sub(start))
+	assert.Equal(t, time.Date(2023, 3, 25, 6, 0, 0, 0, paris), spec.Start(time.Date(2023, 3, 26, 5, 0, 0, 0, paris)))
+}
+
+func TestSpec_Label(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NoError(t, err)
+	start := time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		interval string
+		opts     Options
+		start    time.Time
+		label    time.Time
+	}{
+		{
+			name:     "default",
+			interval: "15m",
+			start:    start,
+			label:    start,
+		},
+		{
+			name:     "start",
+			interval: "15m",
+			opts:     Options{Label: LabelStart},
+			start:    start,
+			label:    start,
+		},
+		{
+			name:     "end",
+			interval: "15m",
+			opts:     Options{Label: LabelEnd},
+			start:    start,
+			label:    start.Add(15 * time.Minute),
+		},
+		{
+			name:     "center",
+			interval: "15m",
+			opts:     Options{Label: LabelCenter},
+			start:    start,
+			label:    start.Add(7*time.Minute + 30*time.Second),
+		},
+		{
+			name:     "center of a 25 hour day",
+			interval: "1d",
+			opts:     Options{Timezone: "Europe/Paris", Label: LabelCenter},
+			start:    time.Date(2023, 10, 29, 0, 0, 0, 0, paris),
+			label:    time.Date(2023, 10, 29, 0, 0, 0, 0, paris).Add(12*time.Hour + 30*time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Parse(tt.interval, tt.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.label, spec.Label(tt.start))
+		})
+	}
+
+	_, err = Parse("15m", Options{Label: "middle"})
+	assert.Error(t, err)
+}
+
+func TestSpec_Validate(t *testing.T) {
+	assert.NoError(t, Fixed(time.Minute).Validate())
+	spec, err := Parse("1mo", Options{})
+	assert.NoError(t, err)
+	assert.NoError(t, spec.Validate())
+	assert.Zero(t, spec.Duration())
+
+	assert.ErrorIs(t, Spec{}.Validate(), reducer.ErrInvalidInterval)
+	assert.ErrorIs(t, Fixed(0).Validate(), reducer.ErrInvalidInterval)
+}
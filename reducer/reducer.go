@@ -1,10 +1,100 @@
 package reducer
 
 import (
+	"context"
+	"fmt"
+
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 )
 
+// checkInterval is the number of points processed between two checks of the context.
+const checkInterval = 1024
+
 // DataReducer defines an interface for reducing and summarizing time series data.
 type DataReducer interface {
 	Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error)
 }
+
+// ContextReducer is implemented by reducers whose reduction can be cancelled.
+// ReduceContext behaves like Reduce, but checks ctx periodically and returns
+// an error wrapping ctx.Err() once it is done.
+type ContextReducer interface {
+	ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error)
+}
+
+// MultiReducer is implemented by reducers producing several series from a single
+// input, such as one series per quantile. The order of the series is documented
+// by each reducer.
+type MultiReducer interface {
+	ReduceMulti(data []datapoint.TimePoint) ([][]datapoint.TimePoint, error)
+}
+
+// StreamReducer reduces time series data incrementally, one point at a time.
+// Points must be pushed in the order they would appear in the slice passed to Reduce.
+type StreamReducer interface {
+	// Push adds a point to the stream and returns the reduced points completed by it, if any.
+	Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error)
+	// Flush returns the reduced points still pending at the end of the stream.
+	// The stream must not be used after Flush.
+	Flush() ([]datapoint.TimePoint, error)
+}
+
+// Streamer is implemented by reducers able to reduce data incrementally.
+// Each call to NewStream returns an independent StreamReducer, so a single
+// reducer can serve several streams concurrently.
+type Streamer interface {
+	NewStream() StreamReducer
+}
+
+// ReduceStream pushes every point of data through stream, flushes it and
+// returns all the reduced points. It is the batch counterpart of a StreamReducer.
+func ReduceStream(stream StreamReducer, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return ReduceStreamContext(context.Background(), stream, data)
+}
+
+// ReduceStreamContext behaves like ReduceStream, but stops with an error
+// wrapping ctx.Err() once ctx is done.
+func ReduceStreamContext(ctx context.Context, stream StreamReducer, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	var reduced []datapoint.TimePoint
+	for i, point := range data {
+		if err := CheckContext(ctx, i); err != nil {
+			return nil, err
+		}
+		out, err := stream.Push(point)
+		if err != nil {
+			return nil, err
+		}
+		reduced = append(reduced, out...)
+	}
+	out, err := stream.Flush()
+	if err != nil {
+		return nil, err
+	}
+	return append(reduced, out...), nil
+}
+
+// ReduceContext reduces data with r, through ReduceContext when r is a
+// ContextReducer. Other reducers cannot be interrupted, so ctx is only checked
+// before calling their Reduce method.
+func ReduceContext(ctx context.Context, r DataReducer, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	if cr, ok := r.(ContextReducer); ok {
+		return cr.ReduceContext(ctx, data)
+	}
+	if err := CheckContext(ctx, 0); err != nil {
+		return nil, err
+	}
+	return r.Reduce(data)
+}
+
+// CheckContext is meant to be called at each iteration i of a reduction loop.
+// Every checkInterval iterations, starting with the first one, it returns an
+// error wrapping ctx.Err() if ctx is done.
+func CheckContext(ctx context.Context, i int) error {
+	if i%checkInterval != 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("reduction interrupted: %w", err)
+	}
+	return nil
+}
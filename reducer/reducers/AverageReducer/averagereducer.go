@@ -1,16 +1,16 @@
 package averagereducer
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"time"
 
 	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 )
 
 // New creates a new instance of AverageReducer with the provided configuration.
-// It parses the interval duration from the configuration and returns an error if the interval is invalid.
+// It parses the interval, either a duration or a calendar interval, and returns an error if it is invalid.
 //
 // Parameters:
 //   - conf: Configuration struct containing the interval as a string.
@@ -19,21 +19,27 @@ import (
 //   - *AverageReducer: A pointer to the newly created AverageReducer instance.
 //   - error: An error if the interval parsing fails.
 func New(conf *Configuration) (reducer.DataReducer, error) {
-	interval, err := time.ParseDuration(conf.Interval)
+	spec, err := interval.Parse(conf.Interval, conf.Options)
 	if err != nil {
-		return nil, fmt.Errorf("invalid interval: %w", err)
+		return nil, err
 	}
-	if interval <= 0 {
-		return nil, fmt.Errorf("interval must be positive, got %v", interval)
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
 	}
+
 	return &AverageReducer{
-		Interval: interval,
+		Interval: spec.Duration(),
+		Input:    input,
+		spec:     spec,
 	}, nil
 }
 
 // AverageReducer reduces data by calculating the average over fixed intervals.
 type AverageReducer struct {
-	Interval time.Duration // Interval in seconds
+	Interval time.Duration // Length of fixed intervals, zero for calendar intervals
+	Input    reducer.Input
+	spec     interval.Spec // Intervals parsed by New, along with their options
 }
 
 // Reduce takes a slice of TimePoint data and reduces it by averaging the values
@@ -49,35 +55,47 @@ type AverageReducer struct {
 //	[]datapoint.TimePoint - A slice of reduced TimePoint data.
 //	error - An error if the input data is empty.
 func (ar *AverageReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
-	if len(data) == 0 {
-		return nil, errors.New("no data to reduce")
-	}
-	var reduced []datapoint.TimePoint
-	var sum float64
-	var count int64
-	startTime := data[0].Timestamp
+	return ar.ReduceContext(context.Background(), data)
+}
 
-	for _, point := range data {
-		if point.Timestamp.Before(startTime.Add(ar.Interval)) {
-			sum += point.Value
-			count++
-		} else {
-			reduced = append(reduced, datapoint.TimePoint{
-				Timestamp: startTime,
-				Value:     sum / float64(count),
-			})
-			startTime = startTime.Add(ar.Interval)
-			sum = point.Value
-			count = 1
-		}
-	}
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (ar *AverageReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return ar.Input.Reduce(ctx, ar.newStream(), data)
+}
 
-	if count > 0 {
-		reduced = append(reduced, datapoint.TimePoint{
-			Timestamp: startTime,
-			Value:     sum / float64(count),
-		})
+// intervals returns the intervals parsed by New, or the fixed intervals of
+// Interval when the reducer was built as a struct literal.
+func (ar *AverageReducer) intervals() interval.Spec {
+	if !ar.spec.IsZero() {
+		return ar.spec
 	}
+	return interval.Fixed(ar.Interval)
+}
+
+// NewStream returns a StreamReducer averaging the pushed points over the reducer's interval.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (ar *AverageReducer) NewStream() reducer.StreamReducer {
+	return ar.Input.Stream(ar.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (ar *AverageReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(ar.intervals(), &averageAggregator{})
+}
+
+// averageAggregator holds the state of the interval currently being averaged.
+type averageAggregator struct {
+	sum   float64
+	count int64
+}
+
+func (a *averageAggregator) Add(point datapoint.TimePoint) {
+	a.sum += point.Value
+	a.count++
+}
 
-	return reduced, nil
+func (a *averageAggregator) Value() float64 {
+	value := a.sum / float64(a.count)
+	a.sum, a.count = 0, 0
+	return value
 }
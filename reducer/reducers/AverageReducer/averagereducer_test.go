@@ -1,9 +1,12 @@
 package averagereducer
 
 import (
+	"math"
 	"testing"
 	"time"
 
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,6 +21,15 @@ func TestNew(t *testing.T) {
 			name: "valid interval",
 			conf: Configuration{Interval: "1m"},
 		},
+		{
+			name: "epoch aligned interval",
+			conf: Configuration{Interval: "15m", Options: interval.Options{Align: interval.AlignEpoch}},
+		},
+		{
+			name:      "invalid alignment",
+			conf:      Configuration{Interval: "15m", Options: interval.Options{Align: "invalid"}},
+			expectErr: true,
+		},
 		{
 			name:      "invalid interval",
 			conf:      Configuration{Interval: "invalid"},
@@ -77,6 +89,19 @@ func TestReduce(t *testing.T) {
 				{Timestamp: time.Unix(60, 0), Value: 3.5},
 			},
 		},
+		{
+			name:     "gap of several intervals",
+			interval: time.Minute,
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(150, 0), Value: 2},
+				{Timestamp: time.Unix(170, 0), Value: 4},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(120, 0), Value: 3},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -92,3 +117,119 @@ func TestReduce(t *testing.T) {
 		})
 	}
 }
+
+func TestNewStream(t *testing.T) {
+	ar := &AverageReducer{Interval: time.Minute}
+	stream := ar.NewStream()
+
+	out, err := stream.Push(datapoint.TimePoint{Timestamp: time.Unix(0, 0), Value: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	out, err = stream.Push(datapoint.TimePoint{Timestamp: time.Unix(30, 0), Value: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	out, err = stream.Push(datapoint.TimePoint{Timestamp: time.Unix(60, 0), Value: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 1.5}}, out)
+
+	out, err = stream.Push(datapoint.TimePoint{Timestamp: time.Unix(90, 0), Value: 4})
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	out, err = stream.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(60, 0), Value: 3.5}}, out)
+}
+
+func TestReduce_Aligned(t *testing.T) {
+	ar, err := New(&Configuration{Interval: "15m", Options: interval.Options{Align: interval.AlignEpoch}})
+	assert.NoError(t, err)
+
+	start := time.Date(2023, 10, 1, 10, 3, 17, 0, time.UTC)
+	result, err := ar.Reduce([]datapoint.TimePoint{
+		{Timestamp: start, Value: 1},
+		{Timestamp: start.Add(10 * time.Minute), Value: 3},
+		{Timestamp: start.Add(15 * time.Minute), Value: 5},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC), Value: 2},
+		{Timestamp: time.Date(2023, 10, 1, 10, 15, 0, 0, time.UTC), Value: 5},
+	}, result)
+}
+
+func TestReduce_Gaps(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(180, 0), Value: 4},
+	}
+	tests := []struct {
+		gaps     string
+		expected []datapoint.TimePoint
+	}{
+		{
+			gaps: interval.GapsSkip,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(180, 0), Value: 4},
+			},
+		},
+		{
+			gaps: interval.GapsPrevious,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: 1},
+				{Timestamp: time.Unix(120, 0), Value: 1},
+				{Timestamp: time.Unix(180, 0), Value: 4},
+			},
+		},
+		{
+			gaps: interval.GapsLinear,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: 2},
+				{Timestamp: time.Unix(120, 0), Value: 3},
+				{Timestamp: time.Unix(180, 0), Value: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.gaps, func(t *testing.T) {
+			ar, err := New(&Configuration{Interval: "1m", Options: interval.Options{Gaps: tt.gaps}})
+			assert.NoError(t, err)
+			result, err := ar.Reduce(data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestReduce_NaN(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(20, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(40, 0), Value: 3},
+	}
+
+	ar, err := New(&Configuration{Interval: "1m"})
+	assert.NoError(t, err)
+	result, err := ar.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 2}}, result, "NaN values are skipped by default")
+
+	ar, err = New(&Configuration{Interval: "1m", InputOptions: reducer.InputOptions{NaN: reducer.NaNPropagate}})
+	assert.NoError(t, err)
+	result, err = ar.Reduce(data)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.True(t, math.IsNaN(result[0].Value))
+}
+
+func TestReduce_ZeroInterval(t *testing.T) {
+	ar := &AverageReducer{}
+	_, err := ar.Reduce([]datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 1}})
+	assert.ErrorIs(t, err, reducer.ErrInvalidInterval)
+}
@@ -0,0 +1,73 @@
+package countreducer
+
+import (
+	"context"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// New creates a new instance of CountReducer with the provided configuration.
+// It parses the interval, either a duration or a calendar interval, and returns an error if it is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CountReducer{
+		Interval: spec,
+		Input:    input,
+	}, nil
+}
+
+// CountReducer reduces data by counting the points over fixed intervals.
+type CountReducer struct {
+	Interval interval.Spec
+	Input    reducer.Input
+}
+
+// Reduce takes a slice of TimePoint data and returns, for each interval holding
+// data, the number of points, stamped with the interval label. The first interval
+// holds the first point and empty intervals follow the gaps option.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (cr *CountReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return cr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (cr *CountReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return cr.Input.Reduce(ctx, cr.newStream(), data)
+}
+
+// NewStream returns a StreamReducer counting the points of the pushed points over
+// the reducer's interval.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (cr *CountReducer) NewStream() reducer.StreamReducer {
+	return cr.Input.Stream(cr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (cr *CountReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(cr.Interval, &countAggregator{})
+}
+
+// countAggregator holds the state of the interval currently being reduced.
+type countAggregator struct {
+	count float64
+}
+
+func (a *countAggregator) Add(point datapoint.TimePoint) {
+	a.count++
+}
+
+func (a *countAggregator) Value() float64 {
+	value := a.count
+	a.count = 0
+	return value
+}
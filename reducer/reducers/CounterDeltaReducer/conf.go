@@ -0,0 +1,18 @@
+package counterdeltareducer
+
+import (
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+)
+
+// Configuration holds the interval, the value at which the counter wraps
+// around (0 disables rollover detection, 4294967296 for a 32-bit register) and
+// whether deltas are interpolated across interval boundaries.
+type Configuration struct {
+	Interval    string  `json:"interval" required:"true" description:"Length of the intervals: a duration (15m) or a calendar interval (1d, 1w, 1mo, 1y)"`
+	Wrap        float64 `json:"wrap" minimum:"0" description:"Value at which the counter wraps around, 0 disables rollover detection"`
+	Interpolate bool    `json:"interpolate" description:"Split the delta between two readings across the intervals they straddle"`
+
+	interval.Options     `json:",squash"`
+	reducer.InputOptions `json:",squash"`
+}
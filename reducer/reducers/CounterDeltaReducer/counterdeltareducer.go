@@ -0,0 +1,216 @@
+package counterdeltareducer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// New creates a new instance of CounterDeltaReducer with the provided configuration.
+// It parses the interval and validates the wrap value.
+//
+// Parameters:
+//   - conf: Configuration struct containing the interval, wrap value and interpolation flag.
+//
+// Returns:
+//   - *CounterDeltaReducer: A pointer to the newly created CounterDeltaReducer instance.
+//   - error: An error if the interval or the wrap value is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+	// Every interval is emitted, with a zero value when nothing was consumed
+	if err := conf.Options.RejectGaps(); err != nil {
+		return nil, err
+	}
+	if conf.Wrap < 0 {
+		return nil, fmt.Errorf("%w: wrap must not be negative, got %v", reducer.ErrInvalidConfiguration, conf.Wrap)
+	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CounterDeltaReducer{
+		Interval:    spec,
+		Wrap:        conf.Wrap,
+		Interpolate: conf.Interpolate,
+		Input:       input,
+	}, nil
+}
+
+// CounterDeltaReducer reduces the readings of a monotonically increasing
+// counter (such as an energy meter register) to the consumption within fixed
+// intervals.
+//
+// A decrease of the counter is either a rollover, when Wrap is set and the
+// counter was closer to Wrap than the new value is to the old one, or a reset
+// to zero, in which case the new value is the consumption since the reset.
+type CounterDeltaReducer struct {
+	Interval    interval.Spec
+	Wrap        float64 // Value at which the counter wraps around, 0 if it never does
+	Interpolate bool    // Split deltas across interval boundaries proportionally to time
+	Input       reducer.Input
+}
+
+// Report holds the counter discontinuities seen while reducing.
+type Report struct {
+	Resets    int
+	Rollovers int
+}
+
+// Reduce takes a slice of counter readings and returns the consumption within
+// each interval, the first interval holding the first point. Without
+// interpolation, the delta between two readings is accounted to the interval
+// holding the later one. Intervals between the first and the last reading are
+// always emitted, with a zero value when nothing was consumed.
+// Under the NaNGap policy, the deltas going to or from a NaN reading are left
+// out of the consumption.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (cr *CounterDeltaReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return cr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (cr *CounterDeltaReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	reduced, _, err := cr.ReduceCounterContext(ctx, data)
+	return reduced, err
+}
+
+// ReduceCounter behaves like Reduce and also reports the resets and rollovers detected.
+func (cr *CounterDeltaReducer) ReduceCounter(data []datapoint.TimePoint) ([]datapoint.TimePoint, Report, error) {
+	return cr.ReduceCounterContext(context.Background(), data)
+}
+
+// ReduceCounterContext behaves like ReduceCounter, but stops with an error
+// wrapping ctx.Err() once ctx is done.
+func (cr *CounterDeltaReducer) ReduceCounterContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, Report, error) {
+	stream := cr.newStream()
+	reduced, err := cr.Input.ReduceSignal(ctx, stream, data)
+	if err != nil {
+		return nil, Report{}, err
+	}
+	return reduced, stream.report, nil
+}
+
+// NewStream returns a StreamReducer computing the consumption from the pushed readings.
+// The returned stream also implements Report() Report.
+// Pushing a point older than the previous one returns an error.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (cr *CounterDeltaReducer) NewStream() reducer.StreamReducer {
+	counter := cr.newStream()
+	return &reportingStream{StreamReducer: cr.Input.StreamSignal(counter), counter: counter}
+}
+
+// reportingStream exposes the report of the counterStream wrapped by the input policies.
+type reportingStream struct {
+	reducer.StreamReducer
+	counter *counterStream
+}
+
+// Report returns the resets and rollovers detected so far.
+func (s *reportingStream) Report() Report {
+	return s.counter.Report()
+}
+
+func (cr *CounterDeltaReducer) newStream() *counterStream {
+	return &counterStream{reducer: cr}
+}
+
+// counterStream accumulates the consumption of the interval currently being reduced.
+type counterStream struct {
+	reducer   *CounterDeltaReducer
+	started   bool
+	previous  datapoint.TimePoint
+	startTime time.Time
+	sum       float64
+	report    Report
+	index     int // Index of the next pushed point
+}
+
+// Report returns the resets and rollovers detected so far.
+func (s *counterStream) Report() Report {
+	return s.report
+}
+
+// Push computes the delta since the previous reading and accounts it, closing
+// and returning every interval elapsed since that reading.
+func (s *counterStream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	index := s.index
+	s.index++
+	if !s.started {
+		if err := s.reducer.Interval.Validate(); err != nil {
+			return nil, err
+		}
+		s.started = true
+		s.startTime = s.reducer.Interval.Start(point.Timestamp)
+		s.previous = point
+		return nil, nil
+	}
+	if point.Timestamp.Before(s.previous.Timestamp) {
+		return nil, &reducer.Error{Index: index, Timestamp: point.Timestamp, Err: reducer.ErrUnsorted}
+	}
+
+	delta := s.delta(s.previous.Value, point.Value)
+	if math.IsNaN(delta) && s.reducer.Input.NaN == reducer.NaNGap {
+		delta = 0
+	}
+	span := point.Timestamp.Sub(s.previous.Timestamp)
+	interpolate := s.reducer.Interpolate && span > 0
+
+	var reduced []datapoint.TimePoint
+	from := s.previous.Timestamp
+	for end := s.reducer.Interval.Next(s.startTime); !point.Timestamp.Before(end); end = s.reducer.Interval.Next(s.startTime) {
+		if interpolate {
+			s.sum += delta * float64(end.Sub(from)) / float64(span)
+		}
+		reduced = append(reduced, datapoint.TimePoint{
+			Timestamp: s.reducer.Interval.Label(s.startTime),
+			Value:     s.sum,
+		})
+		s.startTime = end
+		s.sum = 0
+		from = end
+	}
+	if interpolate {
+		s.sum += delta * float64(point.Timestamp.Sub(from)) / float64(span)
+	} else {
+		s.sum += delta
+	}
+	s.previous = point
+	return reduced, nil
+}
+
+// Flush returns the consumption of the last interval.
+func (s *counterStream) Flush() ([]datapoint.TimePoint, error) {
+	if !s.started {
+		return nil, nil
+	}
+	s.started = false
+	return []datapoint.TimePoint{{Timestamp: s.reducer.Interval.Label(s.startTime), Value: s.sum}}, nil
+}
+
+// delta returns the consumption between two readings, recording any reset or rollover.
+// A NaN reading makes the delta NaN without being taken for a reset.
+func (s *counterStream) delta(previous, current float64) float64 {
+	if math.IsNaN(previous) || math.IsNaN(current) {
+		return math.NaN()
+	}
+	if current >= previous {
+		return current - previous
+	}
+	if wrap := s.reducer.Wrap; wrap > 0 {
+		if wrapped := wrap - previous + current; wrapped < previous-current {
+			s.report.Rollovers++
+			return wrapped
+		}
+	}
+	s.report.Resets++
+	return current
+}
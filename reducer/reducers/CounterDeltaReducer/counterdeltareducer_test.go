@@ -0,0 +1,168 @@
+package counterdeltareducer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expectErr bool
+	}{
+		{
+			name: "valid interval",
+			conf: Configuration{Interval: "15m"},
+		},
+		{
+			name: "32-bit register",
+			conf: Configuration{Interval: "15m", Wrap: 1 << 32, Interpolate: true},
+		},
+		{
+			name:      "invalid interval",
+			conf:      Configuration{Interval: "invalid"},
+			expectErr: true,
+		},
+		{
+			name:      "negative wrap",
+			conf:      Configuration{Interval: "15m", Wrap: -1},
+			expectErr: true,
+		},
+		{
+			name:      "unsupported gaps",
+			conf:      Configuration{Interval: "15m", Options: interval.Options{Gaps: interval.GapsNaN}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReduceCounter(t *testing.T) {
+	tests := []struct {
+		name      string
+		reducer   CounterDeltaReducer
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		report    Report
+		expectErr bool
+	}{
+		{
+			name:      "empty data",
+			reducer:   CounterDeltaReducer{Interval: interval.Fixed(time.Minute)},
+			data:      []datapoint.TimePoint{},
+			expectErr: true,
+		},
+		{
+			name:    "increasing counter",
+			reducer: CounterDeltaReducer{Interval: interval.Fixed(time.Minute)},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 100},
+				{Timestamp: time.Unix(30, 0), Value: 110},
+				{Timestamp: time.Unix(60, 0), Value: 115},
+				{Timestamp: time.Unix(90, 0), Value: 130},
+				{Timestamp: time.Unix(180, 0), Value: 140},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 10},
+				{Timestamp: time.Unix(60, 0), Value: 20},
+				{Timestamp: time.Unix(120, 0), Value: 0},
+				{Timestamp: time.Unix(180, 0), Value: 10},
+			},
+		},
+		{
+			name:    "reset to zero",
+			reducer: CounterDeltaReducer{Interval: interval.Fixed(time.Minute)},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 100},
+				{Timestamp: time.Unix(20, 0), Value: 110},
+				{Timestamp: time.Unix(40, 0), Value: 5},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 15},
+			},
+			report: Report{Resets: 1},
+		},
+		{
+			name:    "rollover",
+			reducer: CounterDeltaReducer{Interval: interval.Fixed(time.Minute), Wrap: 1000},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 990},
+				{Timestamp: time.Unix(30, 0), Value: 4},
+				{Timestamp: time.Unix(50, 0), Value: 2},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 16},
+			},
+			report: Report{Resets: 1, Rollovers: 1},
+		},
+		{
+			name:    "interpolated across boundaries",
+			reducer: CounterDeltaReducer{Interval: interval.Fixed(time.Minute), Interpolate: true},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(30, 0), Value: 0},
+				{Timestamp: time.Unix(150, 0), Value: 120},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(30, 0), Value: 60},
+				{Timestamp: time.Unix(90, 0), Value: 60},
+				{Timestamp: time.Unix(150, 0), Value: 0},
+			},
+		},
+		{
+			name:    "unsorted data",
+			reducer: CounterDeltaReducer{Interval: interval.Fixed(time.Minute)},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(30, 0), Value: 1},
+				{Timestamp: time.Unix(0, 0), Value: 2},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, report, err := tt.reducer.ReduceCounter(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+				assert.Equal(t, tt.report, report)
+			}
+		})
+	}
+}
+
+func TestNewStream(t *testing.T) {
+	cr := &CounterDeltaReducer{Interval: interval.Fixed(time.Minute)}
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 100},
+		{Timestamp: time.Unix(20, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(30, 0), Value: 110},
+		{Timestamp: time.Unix(40, 0), Value: 5},
+	}
+
+	// NaN readings are skipped by default, as by ReduceCounter
+	stream := cr.NewStream()
+	result, err := reducer.ReduceStream(stream, data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 15}}, result)
+	assert.Equal(t, Report{Resets: 1}, stream.(interface{ Report() Report }).Report())
+}
@@ -0,0 +1,9 @@
+package dedupereducer
+
+import "github.com/EcoPowerHub/dustbuster/reducer"
+
+// Configuration holds the input options of the reducer. Its duplicates policy
+// defaults to keeping the last of the points sharing a timestamp.
+type Configuration struct {
+	reducer.InputOptions `json:",squash"`
+}
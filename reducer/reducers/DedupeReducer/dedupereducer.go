@@ -0,0 +1,61 @@
+package dedupereducer
+
+import (
+	"context"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// New creates a new instance of DedupeReducer with the provided configuration.
+// The duplicates policy defaults to keeping the last point, as gateways resending
+// a sample after a reconnection resend the same value.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	opts := conf.InputOptions
+	if opts.Duplicates == "" {
+		opts.Duplicates = reducer.DuplicatesLast
+	}
+	input, err := reducer.ParseInput(opts, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DedupeReducer{
+		Input: input,
+	}, nil
+}
+
+// DedupeReducer reduces data by merging the points sharing a timestamp
+// according to the duplicates policy of its Input.
+type DedupeReducer struct {
+	Input reducer.Input
+}
+
+// Reduce returns data with the points sharing a timestamp merged into a single
+// point. Points with distinct timestamps are returned as is.
+// NaN values are dropped under the NaNSkip policy. They are kept under the
+// NaNGap policy, so that the gaps show in the output, and merged like any other
+// value under the NaNPropagate policy.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (dr *DedupeReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return dr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (dr *DedupeReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	// Duplicates are merged by the stream of the reducer
+	return reducer.Input{Order: dr.Input.Order, NaN: dr.Input.NaN}.ReduceSignal(ctx, dr.newStream(), data)
+}
+
+// NewStream returns a StreamReducer merging the consecutive pushed points
+// sharing a timestamp. A merged point is returned once a point with another
+// timestamp is pushed, or by Flush.
+// The NaN policy of Input applies to the pushed points.
+func (dr *DedupeReducer) NewStream() reducer.StreamReducer {
+	return reducer.Input{NaN: dr.Input.NaN}.StreamSignal(dr.newStream())
+}
+
+// newStream returns the stream merging the points left by the NaN policy.
+func (dr *DedupeReducer) newStream() reducer.StreamReducer {
+	return reducer.NewDedupeStream(dr.Input.Duplicates)
+}
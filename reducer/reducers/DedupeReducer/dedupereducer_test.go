@@ -0,0 +1,207 @@
+package dedupereducer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "default policy",
+			conf:     Configuration{},
+			expected: reducer.DuplicatesLast,
+		},
+		{
+			name:     "average",
+			conf:     Configuration{InputOptions: reducer.InputOptions{Duplicates: reducer.DuplicatesAverage}},
+			expected: reducer.DuplicatesAverage,
+		},
+		{
+			name:      "invalid policy",
+			conf:      Configuration{InputOptions: reducer.InputOptions{Duplicates: "drop"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, r.(*DedupeReducer).Input.Duplicates)
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(60, 0), Value: 2},
+		{Timestamp: time.Unix(60, 0), Value: 4},
+		{Timestamp: time.Unix(120, 0), Value: 5},
+	}
+
+	tests := []struct {
+		name       string
+		duplicates string
+		data       []datapoint.TimePoint
+		expected   []datapoint.TimePoint
+		err        error
+	}{
+		{
+			name: "keep last",
+			data: data,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: 4},
+				{Timestamp: time.Unix(120, 0), Value: 5},
+			},
+		},
+		{
+			name:       "sum",
+			duplicates: reducer.DuplicatesSum,
+			data:       data,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: 6},
+				{Timestamp: time.Unix(120, 0), Value: 5},
+			},
+		},
+		{
+			name:       "error",
+			duplicates: reducer.DuplicatesError,
+			data:       data,
+			err:        reducer.ErrDuplicate,
+		},
+		{
+			name: "no data",
+			data: []datapoint.TimePoint{},
+			err:  reducer.ErrNoData,
+		},
+		{
+			name: "unsorted data",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(60, 0), Value: 1},
+				{Timestamp: time.Unix(0, 0), Value: 2},
+			},
+			err: reducer.ErrUnsorted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(&Configuration{InputOptions: reducer.InputOptions{Duplicates: tt.duplicates}})
+			assert.NoError(t, err)
+			result, err := r.Reduce(tt.data)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestNewStream(t *testing.T) {
+	r, err := New(&Configuration{InputOptions: reducer.InputOptions{Duplicates: reducer.DuplicatesAverage}})
+	assert.NoError(t, err)
+	stream := r.(reducer.Streamer).NewStream()
+
+	reduced, err := stream.Push(datapoint.TimePoint{Timestamp: time.Unix(0, 0), Value: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, reduced)
+	reduced, err = stream.Push(datapoint.TimePoint{Timestamp: time.Unix(0, 0), Value: 3})
+	assert.NoError(t, err)
+	assert.Empty(t, reduced)
+	reduced, err = stream.Push(datapoint.TimePoint{Timestamp: time.Unix(60, 0), Value: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 2}}, reduced)
+
+	reduced, err = stream.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(60, 0), Value: 5}}, reduced)
+}
+
+func TestReduce_NaN(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(60, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(120, 0), Value: 3},
+		{Timestamp: time.Unix(120, 0), Value: math.NaN()},
+	}
+
+	tests := []struct {
+		nan      string
+		expected []datapoint.TimePoint
+	}{
+		{
+			nan: reducer.NaNSkip,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(120, 0), Value: 3},
+			},
+		},
+		{
+			nan: reducer.NaNGap,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: math.NaN()},
+				{Timestamp: time.Unix(120, 0), Value: math.NaN()},
+			},
+		},
+		{
+			nan: reducer.NaNPropagate,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: math.NaN()},
+				{Timestamp: time.Unix(120, 0), Value: math.NaN()},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nan, func(t *testing.T) {
+			r, err := New(&Configuration{InputOptions: reducer.InputOptions{NaN: tt.nan}})
+			assert.NoError(t, err)
+			result, err := r.Reduce(data)
+			assert.NoError(t, err)
+			assertPoints(t, tt.expected, result)
+
+			// Streams apply the same policy
+			result, err = reducer.ReduceStream(r.(reducer.Streamer).NewStream(), data)
+			assert.NoError(t, err)
+			assertPoints(t, tt.expected, result)
+		})
+	}
+}
+
+// assertPoints asserts that two series are equal, NaN values included.
+func assertPoints(t *testing.T, expected, actual []datapoint.TimePoint) {
+	t.Helper()
+	if !assert.Len(t, actual, len(expected)) {
+		return
+	}
+	for i := range expected {
+		assert.Equal(t, expected[i].Timestamp, actual[i].Timestamp)
+		if math.IsNaN(expected[i].Value) {
+			assert.True(t, math.IsNaN(actual[i].Value), "value %d should be NaN", i)
+		} else {
+			assert.Equal(t, expected[i].Value, actual[i].Value)
+		}
+	}
+}
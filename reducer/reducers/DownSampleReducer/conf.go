@@ -1,5 +1,9 @@
 package downsamplereducer
 
+import "github.com/EcoPowerHub/dustbuster/reducer"
+
 type Configuration struct {
-	Step int `json:"step"`
+	Step int `json:"step" required:"true" minimum:"1" description:"Keep one point out of step"`
+
+	reducer.InputOptions `json:",squash"`
 }
@@ -1,7 +1,8 @@
 package downsamplereducer
 
 import (
-	"errors"
+	"context"
+	"fmt"
 
 	"github.com/EcoPowerHub/dustbuster/reducer"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
@@ -9,19 +10,33 @@ import (
 
 func New(conf *Configuration) (reducer.DataReducer, error) {
 	if conf == nil {
-		return nil, errors.New("configuration cannot be nil")
+		return nil, fmt.Errorf("%w: configuration cannot be nil", reducer.ErrInvalidConfiguration)
 	}
 	if conf.Step <= 0 {
-		return nil, errors.New("step must be greater than zero")
+		return nil, fmt.Errorf("%w: must be greater than zero, got %d", reducer.ErrInvalidStep, conf.Step)
 	}
+	// Points are selected by their position, so NaN values are kept by default
+	// rather than shifting the selection
+	opts := conf.InputOptions
+	if opts.NaN == "" {
+		opts.NaN = reducer.NaNPropagate
+	}
+	// Every Nth point has always been selected whatever the order of the input
+	input, err := reducer.ParseInput(opts, reducer.OrderTrust)
+	if err != nil {
+		return nil, err
+	}
+
 	return &DownsampleReducer{
-		Step: conf.Step,
+		Step:  conf.Step,
+		Input: input,
 	}, nil
 }
 
 // DownsampleReducer reduces data by selecting every Nth point.
 type DownsampleReducer struct {
-	Step int
+	Step  int
+	Input reducer.Input
 }
 
 // Reduce downsamples the given slice of TimePoint data by selecting every nth element,
@@ -35,35 +50,71 @@ type DownsampleReducer struct {
 //   - A slice of downsampled TimePoint.
 //   - An error if the input data is empty or the Step value is invalid.
 func (dr *DownsampleReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
-	if len(data) == 0 {
-		return nil, errors.New("no data to reduce")
-	}
+	return dr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (dr *DownsampleReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
 	if dr.Step <= 0 {
-		return nil, errors.New("invalid step value")
+		return nil, reducer.ErrInvalidStep
 	}
 
-	// Protect against integer overflow in capacity calculation
-	if len(data) > (1<<31-1)/2 {
-		return nil, errors.New("input data too large")
+	return dr.input().Reduce(ctx, dr.newStream(), data)
+}
+
+// input returns the input policies, with the defaults of New when the reducer
+// was built as a struct literal.
+func (dr *DownsampleReducer) input() reducer.Input {
+	input := dr.Input
+	if input.Order == "" {
+		// Every Nth point has always been selected whatever the order of the input
+		input.Order = reducer.OrderTrust
+	}
+	if input.NaN == "" {
+		input.NaN = reducer.NaNPropagate
 	}
+	return input
+}
+
+// NewStream returns a StreamReducer selecting every Nth pushed point. As with
+// Reduce, the first point is always kept and the last one is returned by Flush
+// when it was not already selected.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (dr *DownsampleReducer) NewStream() reducer.StreamReducer {
+	return dr.input().Stream(dr.newStream())
+}
 
-	// Pre-allocate capacity for better performance
-	capacity := (len(data) + dr.Step - 1) / dr.Step
-	reduced := make([]datapoint.TimePoint, 0, capacity)
+// newStream returns the stream reducing the points left by the input policies.
+func (dr *DownsampleReducer) newStream() reducer.StreamReducer {
+	return &downsampleStream{step: dr.Step}
+}
 
-	// Always include the first point
-	reduced = append(reduced, data[0])
+// downsampleStream tracks the index of the pushed points and the last one seen.
+type downsampleStream struct {
+	step  int
+	count int
+	last  datapoint.TimePoint
+}
 
-	// Process intermediate points
-	for i := dr.Step; i < len(data); i += dr.Step {
-		reduced = append(reduced, data[i])
+func (s *downsampleStream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	if s.step <= 0 {
+		return nil, reducer.ErrInvalidStep
+	}
+	idx := s.count
+	s.count++
+	s.last = point
+	if idx%s.step == 0 {
+		return []datapoint.TimePoint{point}, nil
 	}
+	return nil, nil
+}
 
+func (s *downsampleStream) Flush() ([]datapoint.TimePoint, error) {
 	// Always include the last point if it's not already included
-	lastIdx := len(data) - 1
-	if lastIdx > 0 && lastIdx%dr.Step != 0 {
-		reduced = append(reduced, data[len(data)-1])
+	lastIdx := s.count - 1
+	s.count = 0
+	if lastIdx > 0 && lastIdx%s.step != 0 {
+		return []datapoint.TimePoint{s.last}, nil
 	}
-
-	return reduced, nil
+	return nil, nil
 }
@@ -2,18 +2,14 @@ package downsamplereducer
 
 import (
 	"errors"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/EcoPowerHub/dustbuster/reducer"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 )
 
-// Define error variables for consistent error handling
-var (
-	ErrNoData      = errors.New("no data to reduce")
-	ErrInvalidStep = errors.New("invalid step value")
-)
-
 // TestDownsampleReducer_Reduce tests the DownsampleReducer's Reduce function.
 func TestDownsampleReducer_Reduce(t *testing.T) {
 	tests := []struct {
@@ -46,7 +42,7 @@ func TestDownsampleReducer_Reduce(t *testing.T) {
 			data:        []datapoint.TimePoint{},
 			want:        nil,
 			wantErr:     true,
-			expectedErr: ErrNoData,
+			expectedErr: reducer.ErrNoData,
 		},
 		{
 			name: "invalid step",
@@ -57,7 +53,7 @@ func TestDownsampleReducer_Reduce(t *testing.T) {
 			},
 			want:        nil,
 			wantErr:     true,
-			expectedErr: ErrInvalidStep,
+			expectedErr: reducer.ErrInvalidStep,
 		},
 		{
 			name: "step greater than data length",
@@ -96,8 +92,8 @@ func TestDownsampleReducer_Reduce(t *testing.T) {
 				t.Errorf("Reduce() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if err != nil && err.Error() != tt.expectedErr.Error() {
-				t.Errorf("Reduce() error = %v, expectedErr %v", err, tt.expectedErr.Error())
+			if err != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Reduce() error = %v, expectedErr %v", err, tt.expectedErr)
 			}
 			if !equalPoints(got, tt.want) {
 				t.Errorf("Reduce() got = %v, want %v", got, tt.want)
@@ -118,3 +114,134 @@ func equalPoints(a, b []datapoint.TimePoint) bool {
 	}
 	return true
 }
+
+// TestDownsampleReducer_NewStream checks that streaming points yields the same result as Reduce.
+func TestDownsampleReducer_NewStream(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(1, 0), Value: 1.0},
+		{Timestamp: time.Unix(2, 0), Value: 2.0},
+		{Timestamp: time.Unix(3, 0), Value: 3.0},
+		{Timestamp: time.Unix(4, 0), Value: 4.0},
+	}
+	dr := &DownsampleReducer{Step: 2}
+	stream := dr.NewStream()
+
+	var got []datapoint.TimePoint
+	for _, point := range data {
+		out, err := stream.Push(point)
+		if err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+		got = append(got, out...)
+	}
+	out, err := stream.Flush()
+	if err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	got = append(got, out...)
+
+	want, err := dr.Reduce(data)
+	if err != nil {
+		t.Fatalf("Reduce() error = %v", err)
+	}
+	if !equalPoints(got, want) {
+		t.Errorf("stream got = %v, want %v", got, want)
+	}
+}
+
+// TestNew_Order checks that the order of the input is trusted unless configured otherwise.
+func TestNew_Order(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(3, 0), Value: 3.0},
+		{Timestamp: time.Unix(1, 0), Value: 1.0},
+		{Timestamp: time.Unix(2, 0), Value: 2.0},
+	}
+	tests := []struct {
+		name        string
+		order       string
+		want        []datapoint.TimePoint
+		expectedErr error
+	}{
+		{
+			name:  "default",
+			order: "",
+			want:  []datapoint.TimePoint{data[0], data[2]},
+		},
+		{
+			name:  "sort",
+			order: reducer.OrderSort,
+			want:  []datapoint.TimePoint{data[1], data[0]},
+		},
+		{
+			name:        "reject",
+			order:       reducer.OrderReject,
+			expectedErr: reducer.ErrUnsorted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dr, err := New(&Configuration{Step: 2, InputOptions: reducer.InputOptions{Order: tt.order}})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got, err := dr.Reduce(data)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Reduce() error = %v, expectedErr %v", err, tt.expectedErr)
+			}
+			if !equalPoints(got, tt.want) {
+				t.Errorf("Reduce() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Reducers built without New trust the order as well
+	got, err := (&DownsampleReducer{Step: 2}).Reduce(data)
+	if err != nil {
+		t.Fatalf("Reduce() error = %v", err)
+	}
+	if !equalPoints(got, []datapoint.TimePoint{data[0], data[2]}) {
+		t.Errorf("Reduce() got = %v", got)
+	}
+}
+
+// TestNew_NaN checks that NaN values are selected like any other point unless configured otherwise.
+func TestNew_NaN(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(1, 0), Value: 1.0},
+		{Timestamp: time.Unix(2, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(3, 0), Value: 3.0},
+		{Timestamp: time.Unix(4, 0), Value: 4.0},
+	}
+	tests := []struct {
+		name string
+		nan  string
+		want []datapoint.TimePoint
+	}{
+		{name: "default", nan: "", want: []datapoint.TimePoint{data[0], data[2], data[3]}},
+		{name: "skip", nan: reducer.NaNSkip, want: []datapoint.TimePoint{data[0], data[3]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dr, err := New(&Configuration{Step: 2, InputOptions: reducer.InputOptions{NaN: tt.nan}})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got, err := dr.Reduce(data)
+			if err != nil {
+				t.Fatalf("Reduce() error = %v", err)
+			}
+			if !equalPoints(got, tt.want) {
+				t.Errorf("Reduce() got = %v, want %v", got, tt.want)
+			}
+			got, err = reducer.ReduceStream(dr.(reducer.Streamer).NewStream(), data)
+			if err != nil {
+				t.Fatalf("ReduceStream() error = %v", err)
+			}
+			if !equalPoints(got, tt.want) {
+				t.Errorf("ReduceStream() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
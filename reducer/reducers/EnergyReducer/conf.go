@@ -0,0 +1,20 @@
+package energyreducer
+
+import (
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+)
+
+// Configuration holds the interval, the integration method ("trapezoidal" or
+// "left", defaults to "trapezoidal"), the time unit of the integral (defaults to
+// "1h", turning kW into kWh) and a scale factor applied to the result (defaults
+// to 1, use 0.001 to turn W into kWh).
+type Configuration struct {
+	Interval string  `json:"interval" required:"true" description:"Length of the intervals: a duration (15m) or a calendar interval (1d, 1w, 1mo, 1y)"`
+	Method   string  `json:"method" default:"trapezoidal" enum:"trapezoidal,left" description:"Integration method"`
+	Unit     string  `json:"unit" default:"1h" description:"Time unit of the integral, 1h turns kW into kWh"`
+	Scale    float64 `json:"scale" default:"1" description:"Factor applied to the integral, 0.001 turns W into kWh"`
+
+	interval.Options     `json:",squash"`
+	reducer.InputOptions `json:",squash"`
+}
@@ -0,0 +1,158 @@
+package energyreducer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+const (
+	// MethodTrapezoidal interpolates linearly between two consecutive samples.
+	MethodTrapezoidal = "trapezoidal"
+	// MethodLeft holds each value until the next sample (left Riemann sum).
+	MethodLeft = "left"
+)
+
+// New creates a new instance of EnergyReducer with the provided configuration.
+// It parses the interval and unit durations and validates the integration method.
+//
+// Parameters:
+//   - conf: Configuration struct containing the interval, method, unit and scale.
+//
+// Returns:
+//   - *EnergyReducer: A pointer to the newly created EnergyReducer instance.
+//   - error: An error if any of the configuration values is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+	// The signal is integrated over every interval, none of them is empty
+	if err := conf.Options.RejectGaps(); err != nil {
+		return nil, err
+	}
+
+	method := conf.Method
+	switch method {
+	case "":
+		method = MethodTrapezoidal
+	case MethodTrapezoidal, MethodLeft:
+	default:
+		return nil, fmt.Errorf("%w: invalid method: %q", reducer.ErrInvalidConfiguration, conf.Method)
+	}
+
+	unit := time.Hour
+	if conf.Unit != "" {
+		unit, err = time.ParseDuration(conf.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid unit: %w", reducer.ErrInvalidConfiguration, err)
+		}
+		if unit <= 0 {
+			return nil, fmt.Errorf("%w: unit must be positive, got %v", reducer.ErrInvalidConfiguration, unit)
+		}
+	}
+
+	scale := conf.Scale
+	if scale == 0 {
+		scale = 1
+	}
+
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EnergyReducer{
+		Interval: spec,
+		Method:   method,
+		Unit:     unit,
+		Scale:    scale,
+		Input:    input,
+	}, nil
+}
+
+// EnergyReducer reduces instantaneous values (power) by integrating them over
+// time within fixed intervals (energy).
+type EnergyReducer struct {
+	Interval interval.Spec
+	Method   string
+	Unit     time.Duration // Time unit of the integral, one hour for kW to kWh
+	Scale    float64       // Factor applied to the integral, 0.001 for W to kWh
+	Input    reducer.Input
+}
+
+// Reduce takes a slice of TimePoint data and returns the integral of the signal
+// over each interval, the first interval holding the first point. A segment
+// between two samples straddling an interval boundary is split proportionally
+// between both intervals. Intervals between the first and last point are always
+// emitted, even without samples of their own.
+// Under the NaNGap policy, the segments going to or from a NaN value are left
+// out of the integral, and an interval covered only by them is NaN.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (er *EnergyReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return er.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (er *EnergyReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return er.Input.ReduceSignal(ctx, er.newStream(), data)
+}
+
+// NewStream returns a StreamReducer integrating the pushed points.
+// Pushing a point older than the previous one returns an error.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (er *EnergyReducer) NewStream() reducer.StreamReducer {
+	return er.Input.StreamSignal(er.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (er *EnergyReducer) newStream() reducer.StreamReducer {
+	return &energyStream{
+		reducer:    er,
+		integrator: interval.NewIntegrator(er.Interval, er.Method == MethodTrapezoidal, er.Input.NaN == reducer.NaNGap),
+	}
+}
+
+// energyStream scales the integral of the signal over each interval.
+type energyStream struct {
+	reducer    *EnergyReducer
+	integrator *interval.Integrator
+}
+
+// Push integrates the segment between the previous point and this one,
+// returning the energy of every interval the segment closes.
+func (s *energyStream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	integrals, err := s.integrator.Push(point)
+	if err != nil {
+		return nil, err
+	}
+	var reduced []datapoint.TimePoint
+	for _, integral := range integrals {
+		reduced = append(reduced, s.energy(integral))
+	}
+	return reduced, nil
+}
+
+// Flush returns the energy of the last interval, unless it holds only the last point.
+func (s *energyStream) Flush() ([]datapoint.TimePoint, error) {
+	integral, _, ok := s.integrator.Flush()
+	if !ok || integral.Covered == 0 {
+		return nil, nil
+	}
+	return []datapoint.TimePoint{s.energy(integral)}, nil
+}
+
+// energy returns the scaled integral of an interval, NaN when the interval is
+// not covered at all, which only happens across a gap.
+func (s *energyStream) energy(integral interval.Integral) datapoint.TimePoint {
+	value := math.NaN()
+	if integral.Covered > 0 {
+		value = integral.Area / float64(s.reducer.Unit) * s.reducer.Scale
+	}
+	return datapoint.TimePoint{Timestamp: s.reducer.Interval.Label(integral.Start), Value: value}
+}
@@ -0,0 +1,181 @@
+package energyreducer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expectErr bool
+	}{
+		{
+			name: "defaults",
+			conf: Configuration{Interval: "15m"},
+		},
+		{
+			name: "left riemann in watts",
+			conf: Configuration{Interval: "1h", Method: MethodLeft, Unit: "1h", Scale: 0.001},
+		},
+		{
+			name:      "invalid interval",
+			conf:      Configuration{Interval: "invalid"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid method",
+			conf:      Configuration{Interval: "1h", Method: "simpson"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid unit",
+			conf:      Configuration{Interval: "1h", Unit: "-1h"},
+			expectErr: true,
+		},
+		{
+			name:      "unsupported gaps",
+			conf:      Configuration{Interval: "1h", Options: interval.Options{Gaps: interval.GapsNaN}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	start := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		conf      Configuration
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		expectErr bool
+	}{
+		{
+			name:      "empty data",
+			conf:      Configuration{Interval: "1h"},
+			data:      []datapoint.TimePoint{},
+			expectErr: true,
+		},
+		{
+			name: "constant power",
+			conf: Configuration{Interval: "1h"},
+			data: []datapoint.TimePoint{
+				{Timestamp: start, Value: 10},
+				{Timestamp: start.Add(30 * time.Minute), Value: 10},
+				{Timestamp: start.Add(time.Hour), Value: 10},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: start, Value: 10},
+			},
+		},
+		{
+			name: "trapezoidal segment split across intervals",
+			conf: Configuration{Interval: "1h", Method: MethodTrapezoidal},
+			data: []datapoint.TimePoint{
+				{Timestamp: start, Value: 0},
+				{Timestamp: start.Add(2 * time.Hour), Value: 20},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: start, Value: 5},
+				{Timestamp: start.Add(time.Hour), Value: 15},
+			},
+		},
+		{
+			name: "left riemann segment split across intervals",
+			conf: Configuration{Interval: "1h", Method: MethodLeft},
+			data: []datapoint.TimePoint{
+				{Timestamp: start, Value: 4},
+				{Timestamp: start.Add(90 * time.Minute), Value: 20},
+				{Timestamp: start.Add(2 * time.Hour), Value: 0},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: start, Value: 4},
+				{Timestamp: start.Add(time.Hour), Value: 12},
+			},
+		},
+		{
+			name: "watts to kilowatt-hours per quarter",
+			conf: Configuration{Interval: "15m", Scale: 0.001},
+			data: []datapoint.TimePoint{
+				{Timestamp: start, Value: 2000},
+				{Timestamp: start.Add(30 * time.Minute), Value: 2000},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: start, Value: 0.5},
+				{Timestamp: start.Add(15 * time.Minute), Value: 0.5},
+			},
+		},
+		{
+			name: "NaN values skipped",
+			conf: Configuration{Interval: "1h", Method: MethodTrapezoidal},
+			data: []datapoint.TimePoint{
+				{Timestamp: start, Value: 0},
+				{Timestamp: start.Add(30 * time.Minute), Value: math.NaN()},
+				{Timestamp: start.Add(2 * time.Hour), Value: 20},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: start, Value: 5},
+				{Timestamp: start.Add(time.Hour), Value: 15},
+			},
+		},
+		{
+			name: "NaN values left out as gaps",
+			conf: Configuration{Interval: "1h", Method: MethodLeft, InputOptions: reducer.InputOptions{NaN: reducer.NaNGap}},
+			data: []datapoint.TimePoint{
+				{Timestamp: start, Value: 10},
+				{Timestamp: start.Add(30 * time.Minute), Value: math.NaN()},
+				{Timestamp: start.Add(time.Hour), Value: 10},
+				{Timestamp: start.Add(2 * time.Hour), Value: 10},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: start, Value: 5},
+				{Timestamp: start.Add(time.Hour), Value: 10},
+			},
+		},
+		{
+			name: "unsorted data",
+			conf: Configuration{Interval: "1h"},
+			data: []datapoint.TimePoint{
+				{Timestamp: start.Add(time.Minute), Value: 1},
+				{Timestamp: start, Value: 1},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			er, err := New(&tt.conf)
+			assert.NoError(t, err)
+			result, err := er.Reduce(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, result, len(tt.expected))
+			for i := range tt.expected {
+				assert.Equal(t, tt.expected[i].Timestamp, result[i].Timestamp)
+				assert.InDelta(t, tt.expected[i].Value, result[i].Value, 1e-9)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package firstreducer
+
+import (
+	"context"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// New creates a new instance of FirstReducer with the provided configuration.
+// It parses the interval, either a duration or a calendar interval, and returns an error if it is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FirstReducer{
+		Interval: spec,
+		Input:    input,
+	}, nil
+}
+
+// FirstReducer reduces data by keeping the first value over fixed intervals.
+type FirstReducer struct {
+	Interval interval.Spec
+	Input    reducer.Input
+}
+
+// Reduce takes a slice of TimePoint data and returns, for each interval holding
+// data, the value of the first point, stamped with the interval label. The first
+// interval holds the first point and empty intervals follow the gaps option.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (fr *FirstReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return fr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (fr *FirstReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return fr.Input.Reduce(ctx, fr.newStream(), data)
+}
+
+// NewStream returns a StreamReducer keeping the first value of the pushed points
+// over the reducer's interval.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (fr *FirstReducer) NewStream() reducer.StreamReducer {
+	return fr.Input.Stream(fr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (fr *FirstReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(fr.Interval, &firstAggregator{})
+}
+
+// firstAggregator holds the state of the interval currently being reduced.
+type firstAggregator struct {
+	first float64
+	set   bool
+}
+
+func (a *firstAggregator) Add(point datapoint.TimePoint) {
+	if !a.set {
+		a.first = point.Value
+		a.set = true
+	}
+}
+
+func (a *firstAggregator) Value() float64 {
+	a.set = false
+	return a.first
+}
@@ -0,0 +1,106 @@
+package firstreducer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expectErr bool
+	}{
+		{
+			name: "valid interval",
+			conf: Configuration{Interval: "1m"},
+		},
+		{
+			name:      "invalid interval",
+			conf:      Configuration{Interval: "invalid"},
+			expectErr: true,
+		},
+		{
+			name:      "negative interval",
+			conf:      Configuration{Interval: "-1m"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		expectErr bool
+	}{
+		{
+			name:      "empty data",
+			data:      []datapoint.TimePoint{},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name: "single point",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 42},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 42},
+			},
+		},
+		{
+			name: "multiple intervals",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(30, 0), Value: 2},
+				{Timestamp: time.Unix(60, 0), Value: 3},
+				{Timestamp: time.Unix(90, 0), Value: 4},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: 3},
+			},
+		},
+		{
+			name: "empty intervals are skipped",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(150, 0), Value: 1},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(120, 0), Value: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &FirstReducer{Interval: interval.Fixed(time.Minute)}
+			result, err := r.Reduce(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package lttbreducer
+
+import "github.com/EcoPowerHub/dustbuster/reducer"
+
+type Configuration struct {
+	Points int `json:"points" required:"true" minimum:"3" description:"Number of points to keep"`
+
+	reducer.InputOptions `json:",squash"`
+}
@@ -0,0 +1,145 @@
+package lttbreducer
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// New creates a new instance of LTTBReducer with the provided configuration.
+// The number of points must be at least 3: the first and last points are always
+// kept and at least one bucket is needed in between.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("%w: configuration cannot be nil", reducer.ErrInvalidConfiguration)
+	}
+	if conf.Points < 3 {
+		return nil, fmt.Errorf("%w: points must be at least 3", reducer.ErrInvalidConfiguration)
+	}
+	// Points are selected by their position, so NaN values are kept by default
+	// rather than shifting the selection
+	opts := conf.InputOptions
+	if opts.NaN == "" {
+		opts.NaN = reducer.NaNPropagate
+	}
+	input, err := reducer.ParseInput(opts, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LTTBReducer{
+		Points: conf.Points,
+		Input:  input,
+	}, nil
+}
+
+// LTTBReducer reduces data to a target number of points using the
+// Largest-Triangle-Three-Buckets algorithm, which keeps the visual shape of the
+// series (spikes included) when it is plotted.
+type LTTBReducer struct {
+	Points int
+	Input  reducer.Input
+}
+
+// Reduce downsamples the given slice of TimePoint data to at most Points points.
+// The data is split into Points-2 buckets; from each bucket the point forming the
+// largest triangle with the previously selected point and the average of the next
+// bucket is kept. The first and last points are always kept. If the data already
+// holds no more than Points points, a copy of it is returned.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+//
+// Parameters:
+//   - data: A slice of TimePoint to be downsampled.
+//
+// Returns:
+//   - A slice of downsampled TimePoint.
+//   - An error if the input data is empty or the Points value is invalid.
+func (lr *LTTBReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return lr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (lr *LTTBReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	if len(data) == 0 {
+		return nil, reducer.ErrNoData
+	}
+	input := lr.Input
+	if input.NaN == "" {
+		// NaN values are kept by default, as by New
+		input.NaN = reducer.NaNPropagate
+	}
+	data, err := input.Prepare(data)
+	if err != nil {
+		return nil, err
+	}
+	if lr.Points < 3 {
+		return nil, fmt.Errorf("%w: invalid points value", reducer.ErrInvalidConfiguration)
+	}
+	if len(data) <= lr.Points {
+		return append([]datapoint.TimePoint(nil), data...), nil
+	}
+
+	// Timestamps are expressed relative to the first point to keep float precision
+	origin := data[0].Timestamp
+	x := func(i int) float64 {
+		return float64(data[i].Timestamp.Sub(origin))
+	}
+
+	reduced := make([]datapoint.TimePoint, 0, lr.Points)
+	reduced = append(reduced, data[0])
+
+	every := float64(len(data)-2) / float64(lr.Points-2)
+	selected := 0
+	for bucket := 0; bucket < lr.Points-2; bucket++ {
+		// Average point of the next bucket, the last point being a bucket of its own
+		nextStart := int(float64(bucket+1)*every) + 1
+		nextEnd := int(float64(bucket+2)*every) + 1
+		if nextEnd > len(data) {
+			nextEnd = len(data)
+		}
+		var avgX, avgY float64
+		count := 0
+		for i := nextStart; i < nextEnd; i++ {
+			if math.IsNaN(data[i].Value) {
+				continue
+			}
+			avgX += x(i)
+			avgY += data[i].Value
+			count++
+		}
+		avgX /= float64(count)
+		avgY /= float64(count)
+
+		// Point of the current bucket forming the largest triangle
+		start := int(float64(bucket)*every) + 1
+		end := int(float64(bucket+1)*every) + 1
+		selectedX, selectedY := x(selected), data[selected].Value
+		maxArea := -1.0
+		next := start
+		for i := start; i < end; i++ {
+			// Candidates start at the second point, hence the shift
+			if err := reducer.CheckContext(ctx, i-1); err != nil {
+				return nil, err
+			}
+			// A NaN value, reduced under the propagate NaN policy, is kept so that the gap shows
+			if math.IsNaN(data[i].Value) {
+				next = i
+				break
+			}
+			area := math.Abs((selectedX-avgX)*(data[i].Value-selectedY) - (selectedX-x(i))*(avgY-selectedY))
+			if area > maxArea {
+				maxArea = area
+				next = i
+			}
+		}
+		reduced = append(reduced, data[next])
+		if !math.IsNaN(data[next].Value) {
+			selected = next
+		}
+	}
+
+	return append(reduced, data[len(data)-1]), nil
+}
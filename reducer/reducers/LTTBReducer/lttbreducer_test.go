@@ -0,0 +1,148 @@
+package lttbreducer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      *Configuration
+		expectErr bool
+	}{
+		{
+			name: "valid points",
+			conf: &Configuration{Points: 3},
+		},
+		{
+			name:      "too few points",
+			conf:      &Configuration{Points: 2},
+			expectErr: true,
+		},
+		{
+			name:      "nil configuration",
+			conf:      nil,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLTTBReducer_Reduce(t *testing.T) {
+	tests := []struct {
+		name      string
+		points    int
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		expectErr bool
+	}{
+		{
+			name:      "empty data",
+			points:    3,
+			data:      []datapoint.TimePoint{},
+			expectErr: true,
+		},
+		{
+			name:   "fewer points than target",
+			points: 5,
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(1, 0), Value: 2},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(1, 0), Value: 2},
+			},
+		},
+		{
+			name:   "spike is kept",
+			points: 3,
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0},
+				{Timestamp: time.Unix(1, 0), Value: 1},
+				{Timestamp: time.Unix(2, 0), Value: 50},
+				{Timestamp: time.Unix(3, 0), Value: 1},
+				{Timestamp: time.Unix(4, 0), Value: 0},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0},
+				{Timestamp: time.Unix(2, 0), Value: 50},
+				{Timestamp: time.Unix(4, 0), Value: 0},
+			},
+		},
+		{
+			name:   "largest triangle per bucket",
+			points: 4,
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0},
+				{Timestamp: time.Unix(1, 0), Value: 10},
+				{Timestamp: time.Unix(2, 0), Value: 11},
+				{Timestamp: time.Unix(3, 0), Value: -10},
+				{Timestamp: time.Unix(4, 0), Value: -9},
+				{Timestamp: time.Unix(5, 0), Value: 0},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0},
+				{Timestamp: time.Unix(2, 0), Value: 11},
+				{Timestamp: time.Unix(3, 0), Value: -10},
+				{Timestamp: time.Unix(5, 0), Value: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lr, err := New(&Configuration{Points: tt.points})
+			assert.NoError(t, err)
+			result, err := lr.Reduce(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestLTTBReducer_NaN(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(1, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(2, 0), Value: 3},
+		{Timestamp: time.Unix(3, 0), Value: 4},
+		{Timestamp: time.Unix(4, 0), Value: 5},
+	}
+
+	lr, err := New(&Configuration{Points: 3})
+	assert.NoError(t, err)
+	result, err := lr.Reduce(data)
+	assert.NoError(t, err)
+	if assert.Len(t, result, 3) {
+		assert.True(t, math.IsNaN(result[1].Value), "NaN values are kept by default so that the gap shows")
+	}
+
+	lr, err = New(&Configuration{Points: 3, InputOptions: reducer.InputOptions{NaN: reducer.NaNSkip}})
+	assert.NoError(t, err)
+	result, err = lr.Reduce(data)
+	assert.NoError(t, err)
+	for _, point := range result {
+		assert.False(t, math.IsNaN(point.Value))
+	}
+}
@@ -0,0 +1,13 @@
+package lastreducer
+
+import (
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+)
+
+type Configuration struct {
+	Interval string `json:"interval" required:"true" description:"Length of the intervals: a duration (15m) or a calendar interval (1d, 1w, 1mo, 1y)"`
+
+	interval.Options     `json:",squash"`
+	reducer.InputOptions `json:",squash"`
+}
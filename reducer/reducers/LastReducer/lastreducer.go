@@ -0,0 +1,71 @@
+package lastreducer
+
+import (
+	"context"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// New creates a new instance of LastReducer with the provided configuration.
+// It parses the interval, either a duration or a calendar interval, and returns an error if it is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LastReducer{
+		Interval: spec,
+		Input:    input,
+	}, nil
+}
+
+// LastReducer reduces data by keeping the last value over fixed intervals.
+type LastReducer struct {
+	Interval interval.Spec
+	Input    reducer.Input
+}
+
+// Reduce takes a slice of TimePoint data and returns, for each interval holding
+// data, the value of the last point, stamped with the interval label. The first
+// interval holds the first point and empty intervals follow the gaps option.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (lr *LastReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return lr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (lr *LastReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return lr.Input.Reduce(ctx, lr.newStream(), data)
+}
+
+// NewStream returns a StreamReducer keeping the last value of the pushed points
+// over the reducer's interval.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (lr *LastReducer) NewStream() reducer.StreamReducer {
+	return lr.Input.Stream(lr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (lr *LastReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(lr.Interval, &lastAggregator{})
+}
+
+// lastAggregator holds the state of the interval currently being reduced.
+type lastAggregator struct {
+	last float64
+}
+
+func (a *lastAggregator) Add(point datapoint.TimePoint) {
+	a.last = point.Value
+}
+
+func (a *lastAggregator) Value() float64 {
+	return a.last
+}
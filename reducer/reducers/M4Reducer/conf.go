@@ -0,0 +1,18 @@
+package m4reducer
+
+import (
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+)
+
+// Configuration holds either an Interval, or a pixel Width together with the
+// Start and End (RFC 3339) of the time range rendered by the chart.
+type Configuration struct {
+	Interval string `json:"interval" description:"Length of the intervals: a duration (15m) or a calendar interval (1d, 1w, 1mo, 1y), exclusive with width"`
+	Width    int    `json:"width" minimum:"1" description:"Number of pixel columns of the chart, exclusive with interval"`
+	Start    string `json:"start" description:"RFC 3339 start of the time range rendered by the chart"`
+	End      string `json:"end" description:"RFC 3339 end of the time range rendered by the chart"`
+
+	interval.Options     `json:",squash"`
+	reducer.InputOptions `json:",squash"`
+}
@@ -0,0 +1,244 @@
+package m4reducer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// New creates a new instance of M4Reducer with the provided configuration.
+// Buckets are either intervals, or the pixel columns obtained by splitting the
+// [Start, End] time range into Width buckets.
+//
+// Parameters:
+//   - conf: Configuration struct containing either the interval, or the width and time range.
+//
+// Returns:
+//   - *M4Reducer: A pointer to the newly created M4Reducer instance.
+//   - error: An error if the configuration is incomplete or invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("%w: configuration cannot be nil", reducer.ErrInvalidConfiguration)
+	}
+	if (conf.Interval == "") == (conf.Width == 0) {
+		return nil, fmt.Errorf("%w: exactly one of interval or width must be set", reducer.ErrInvalidConfiguration)
+	}
+	// Selected points keep their timestamps and empty buckets select none
+	if err := conf.Options.RejectGaps(); err != nil {
+		return nil, err
+	}
+	if err := conf.Options.RejectLabel(); err != nil {
+		return nil, err
+	}
+	// Points are selected by their position, so NaN values are kept by default
+	// rather than shifting the selection
+	opts := conf.InputOptions
+	if opts.NaN == "" {
+		opts.NaN = reducer.NaNPropagate
+	}
+	input, err := reducer.ParseInput(opts, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Interval != "" {
+		spec, err := interval.Parse(conf.Interval, conf.Options)
+		if err != nil {
+			return nil, err
+		}
+		return &M4Reducer{Interval: spec, Input: input}, nil
+	}
+
+	if conf.Width < 0 {
+		return nil, fmt.Errorf("%w: width must be positive, got %d", reducer.ErrInvalidConfiguration, conf.Width)
+	}
+	start, err := time.Parse(time.RFC3339, conf.Start)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid start: %w", reducer.ErrInvalidConfiguration, err)
+	}
+	end, err := time.Parse(time.RFC3339, conf.End)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid end: %w", reducer.ErrInvalidConfiguration, err)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("%w: end must be after start", reducer.ErrInvalidConfiguration)
+	}
+	return &M4Reducer{
+		Width: conf.Width,
+		Start: start,
+		End:   end,
+		Input: input,
+	}, nil
+}
+
+// M4Reducer reduces data with the M4 algorithm: for each bucket it keeps the
+// first, minimum, maximum and last points with their original timestamps, which
+// is enough to draw the series at a given chart width without visual error.
+type M4Reducer struct {
+	Interval interval.Spec // Bucket interval, zero in width mode
+	Width    int           // Number of pixel columns between Start and End
+	Start    time.Time
+	End      time.Time
+	Input    reducer.Input
+}
+
+// Reduce returns, for every bucket holding data, its first, minimum, maximum and
+// last points in timestamp order. Points sharing several roles are emitted once.
+// In width mode, points outside the [Start, End] range are dropped.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (mr *M4Reducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return mr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (mr *M4Reducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return mr.input().Reduce(ctx, mr.newStream(), data)
+}
+
+// input returns the input policies, keeping NaN values as New does by default
+// when the reducer was built as a struct literal.
+func (mr *M4Reducer) input() reducer.Input {
+	input := mr.Input
+	if input.NaN == "" {
+		input.NaN = reducer.NaNPropagate
+	}
+	return input
+}
+
+// NewStream returns a StreamReducer applying the M4 algorithm to the pushed points.
+// Pushing a point older than the previous one returns an error.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (mr *M4Reducer) NewStream() reducer.StreamReducer {
+	return mr.input().Stream(mr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (mr *M4Reducer) newStream() reducer.StreamReducer {
+	return &m4Stream{reducer: mr}
+}
+
+// column returns the index of the pixel column holding t.
+// The boolean is false when t lies outside the configured time range.
+func (mr *M4Reducer) column(t time.Time) (int64, bool) {
+	if t.Before(mr.Start) || t.After(mr.End) {
+		return 0, false
+	}
+	column := int64(float64(t.Sub(mr.Start)) / float64(mr.End.Sub(mr.Start)) * float64(mr.Width))
+	if column >= int64(mr.Width) {
+		// The end of the range belongs to the last column
+		column = int64(mr.Width) - 1
+	}
+	return column, true
+}
+
+// m4Stream holds the points selected so far for the current bucket.
+type m4Stream struct {
+	reducer  *M4Reducer
+	started  bool
+	start    time.Time // Start of the current interval in interval mode
+	end      time.Time
+	previous time.Time
+	current  int64
+	count    int
+	first    indexedPoint
+	last     indexedPoint
+	min      indexedPoint
+	max      indexedPoint
+	index    int // Index of the next pushed point
+}
+
+// indexedPoint remembers the position of a point in the stream to keep the output ordered.
+type indexedPoint struct {
+	index int
+	point datapoint.TimePoint
+}
+
+func (s *m4Stream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	index := s.index
+	s.index++
+	if !s.started {
+		if !s.reducer.Interval.IsZero() {
+			if err := s.reducer.Interval.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		s.started = true
+		if !s.reducer.Interval.IsZero() {
+			s.start = s.reducer.Interval.Start(point.Timestamp)
+			s.end = s.reducer.Interval.Next(s.start)
+		}
+	} else if point.Timestamp.Before(s.previous) {
+		return nil, &reducer.Error{Index: index, Timestamp: point.Timestamp, Err: reducer.ErrUnsorted}
+	}
+	s.previous = point.Timestamp
+	bucket, ok := s.bucket(point.Timestamp)
+	if !ok {
+		return nil, nil
+	}
+	current := indexedPoint{index: index, point: point}
+
+	var reduced []datapoint.TimePoint
+	if s.count > 0 && bucket != s.current {
+		reduced = s.emit()
+	}
+	if s.count == 0 {
+		s.current = bucket
+		s.first, s.min, s.max = current, current, current
+	}
+	// The first NaN value, reduced under the propagate NaN policy, takes both
+	// extreme roles so that it shows in the output
+	if !math.IsNaN(s.min.point.Value) && (point.Value < s.min.point.Value || math.IsNaN(point.Value)) {
+		s.min = current
+	}
+	if !math.IsNaN(s.max.point.Value) && (point.Value > s.max.point.Value || math.IsNaN(point.Value)) {
+		s.max = current
+	}
+	s.last = current
+	s.count++
+	return reduced, nil
+}
+
+// bucket returns a key identifying the bucket holding t, the start of its
+// interval or its pixel column. The boolean is false when t lies outside the
+// configured time range.
+func (s *m4Stream) bucket(t time.Time) (int64, bool) {
+	if s.reducer.Interval.IsZero() {
+		return s.reducer.column(t)
+	}
+	if !t.Before(s.end) {
+		s.start = s.reducer.Interval.Advance(s.start, t)
+		s.end = s.reducer.Interval.Next(s.start)
+	}
+	return s.start.UnixNano(), true
+}
+
+func (s *m4Stream) Flush() ([]datapoint.TimePoint, error) {
+	return s.emit(), nil
+}
+
+// emit returns the distinct points selected for the current bucket and resets it.
+func (s *m4Stream) emit() []datapoint.TimePoint {
+	if s.count == 0 {
+		return nil
+	}
+	s.count = 0
+
+	selected := []indexedPoint{s.first, s.min, s.max, s.last}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].index < selected[j].index
+	})
+	reduced := make([]datapoint.TimePoint, 0, len(selected))
+	for i, p := range selected {
+		if i > 0 && p.index == selected[i-1].index {
+			continue
+		}
+		reduced = append(reduced, p.point)
+	}
+	return reduced
+}
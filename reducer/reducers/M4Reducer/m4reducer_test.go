@@ -0,0 +1,205 @@
+package m4reducer
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expectErr bool
+	}{
+		{
+			name: "valid interval",
+			conf: Configuration{Interval: "1m"},
+		},
+		{
+			name: "valid width and range",
+			conf: Configuration{Width: 800, Start: "2023-10-01T00:00:00Z", End: "2023-10-02T00:00:00Z"},
+		},
+		{
+			name:      "invalid interval",
+			conf:      Configuration{Interval: "invalid"},
+			expectErr: true,
+		},
+		{
+			name:      "interval and width",
+			conf:      Configuration{Interval: "1m", Width: 800},
+			expectErr: true,
+		},
+		{
+			name:      "unsupported gaps",
+			conf:      Configuration{Interval: "1m", Options: interval.Options{Gaps: interval.GapsNaN}},
+			expectErr: true,
+		},
+		{
+			name:      "unsupported label",
+			conf:      Configuration{Interval: "1m", Options: interval.Options{Label: interval.LabelEnd}},
+			expectErr: true,
+		},
+		{
+			name:      "width without range",
+			conf:      Configuration{Width: 800},
+			expectErr: true,
+		},
+		{
+			name:      "empty range",
+			conf:      Configuration{Width: 800, Start: "2023-10-01T00:00:00Z", End: "2023-10-01T00:00:00Z"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestM4Reducer_Reduce(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		errIndex  int
+		expectErr bool
+	}{
+		{
+			name:      "empty data",
+			conf:      Configuration{Interval: "1m"},
+			data:      []datapoint.TimePoint{},
+			expectErr: true,
+		},
+		{
+			name: "first min max last per interval",
+			conf: Configuration{Interval: "1m"},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 5},
+				{Timestamp: time.Unix(10, 0), Value: 9},
+				{Timestamp: time.Unix(20, 0), Value: 6},
+				{Timestamp: time.Unix(30, 0), Value: 1},
+				{Timestamp: time.Unix(40, 0), Value: 4},
+				{Timestamp: time.Unix(60, 0), Value: 7},
+				{Timestamp: time.Unix(90, 0), Value: 3},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 5},
+				{Timestamp: time.Unix(10, 0), Value: 9},
+				{Timestamp: time.Unix(30, 0), Value: 1},
+				{Timestamp: time.Unix(40, 0), Value: 4},
+				{Timestamp: time.Unix(60, 0), Value: 7},
+				{Timestamp: time.Unix(90, 0), Value: 3},
+			},
+		},
+		{
+			name: "single point bucket emitted once",
+			conf: Configuration{Interval: "1m"},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 5},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 5},
+			},
+		},
+		{
+			name: "pixel columns drop points outside the range",
+			conf: Configuration{Width: 2, Start: "1970-01-01T00:01:00Z", End: "1970-01-01T00:03:00Z"},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 100},
+				{Timestamp: time.Unix(60, 0), Value: 1},
+				{Timestamp: time.Unix(90, 0), Value: 2},
+				{Timestamp: time.Unix(120, 0), Value: 3},
+				{Timestamp: time.Unix(180, 0), Value: 4},
+				{Timestamp: time.Unix(240, 0), Value: 100},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(60, 0), Value: 1},
+				{Timestamp: time.Unix(90, 0), Value: 2},
+				{Timestamp: time.Unix(120, 0), Value: 3},
+				{Timestamp: time.Unix(180, 0), Value: 4},
+			},
+		},
+		{
+			name: "unsorted data outside the range",
+			conf: Configuration{
+				Width:        2,
+				Start:        "1970-01-01T00:01:00Z",
+				End:          "1970-01-01T00:03:00Z",
+				InputOptions: reducer.InputOptions{Order: reducer.OrderTrust},
+			},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 100},
+				{Timestamp: time.Unix(90, 0), Value: 1},
+				{Timestamp: time.Unix(60, 0), Value: 2},
+			},
+			errIndex:  2,
+			expectErr: true,
+		},
+		{
+			name: "unsorted data",
+			conf: Configuration{Interval: "1m"},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(60, 0), Value: 1},
+				{Timestamp: time.Unix(0, 0), Value: 2},
+			},
+			errIndex:  1,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, err := New(&tt.conf)
+			assert.NoError(t, err)
+			result, err := mr.Reduce(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+				var e *reducer.Error
+				if errors.As(err, &e) {
+					assert.Equal(t, tt.errIndex, e.Index)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestM4Reducer_NaN(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(10, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(20, 0), Value: 3},
+		{Timestamp: time.Unix(30, 0), Value: 2},
+	}
+
+	mr, err := New(&Configuration{Interval: "1m"})
+	assert.NoError(t, err)
+	result, err := mr.Reduce(data)
+	assert.NoError(t, err)
+	if assert.Len(t, result, 3, "the NaN value takes the extreme roles") {
+		assert.True(t, math.IsNaN(result[1].Value))
+	}
+
+	mr, err = New(&Configuration{Interval: "1m", InputOptions: reducer.InputOptions{NaN: reducer.NaNSkip}})
+	assert.NoError(t, err)
+	result, err = mr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{data[0], data[2], data[3]}, result)
+}
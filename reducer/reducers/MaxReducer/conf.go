@@ -1,5 +1,13 @@
 package maxreducer
 
+import (
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+)
+
 type Configuration struct {
-	Interval string `json:"interval"`
+	Interval string `json:"interval" required:"true" description:"Length of the intervals: a duration (15m) or a calendar interval (1d, 1w, 1mo, 1y)"`
+
+	interval.Options     `json:",squash"`
+	reducer.InputOptions `json:",squash"`
 }
@@ -1,67 +1,87 @@
 package maxreducer
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"math"
 	"time"
 
 	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 )
 
 func New(conf *Configuration) (reducer.DataReducer, error) {
-	interval, err := time.ParseDuration(conf.Interval)
+	spec, err := interval.Parse(conf.Interval, conf.Options)
 	if err != nil {
-		return nil, fmt.Errorf("invalid interval: %w", err)
+		return nil, err
 	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
 	return &MaxReducer{
-		Interval: interval,
+		Interval: spec.Duration(),
+		Input:    input,
+		spec:     spec,
 	}, nil
 }
 
 // MaxReducer reduces data by keeping the maximum value over fixed intervals.
 type MaxReducer struct {
-	Interval time.Duration
+	Interval time.Duration // Length of fixed intervals, zero for calendar intervals
+	Input    reducer.Input
+	spec     interval.Spec // Intervals parsed by New, along with their options
 }
 
 // Reduce processes time series data and returns maximum values for each interval.
 // Time complexity: O(n) where n is the number of data points.
-// Assumes input data points are sorted by timestamp in ascending order.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
 func (mr *MaxReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
-	if len(data) == 0 {
-		return nil, errors.New("no data to reduce")
-	}
-	// Validate timestamps are in order
-	for i := 1; i < len(data); i++ {
-		if data[i].Timestamp.Before(data[i-1].Timestamp) {
-			return nil, errors.New("data points must be sorted by timestamp")
-		}
-	}
-	// Pre-allocate slice with estimated capacity
-	estimatedSize := len(data)/2 + 1
-	reduced := make([]datapoint.TimePoint, 0, estimatedSize)
-	startTime := data[0].Timestamp
-	maxValue := -math.MaxFloat64
-	for _, point := range data {
-		if point.Timestamp.Before(startTime.Add(mr.Interval)) {
-			if point.Value > maxValue {
-				maxValue = point.Value
-			}
-		} else {
-			reduced = append(reduced, datapoint.TimePoint{
-				Timestamp: startTime,
-				Value:     maxValue,
-			})
-			startTime = startTime.Add(mr.Interval)
-			maxValue = point.Value
-		}
+	return mr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (mr *MaxReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return mr.Input.Reduce(ctx, mr.newStream(), data)
+}
+
+// intervals returns the intervals parsed by New, or the fixed intervals of
+// Interval when the reducer was built as a struct literal.
+func (mr *MaxReducer) intervals() interval.Spec {
+	if !mr.spec.IsZero() {
+		return mr.spec
 	}
-	if maxValue > -math.MaxFloat64 {
-		reduced = append(reduced, datapoint.TimePoint{
-			Timestamp: startTime,
-			Value:     maxValue,
-		})
+	return interval.Fixed(mr.Interval)
+}
+
+// NewStream returns a StreamReducer keeping the maximum of the pushed points over the reducer's interval.
+// Pushing a point older than the previous one returns an error.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (mr *MaxReducer) NewStream() reducer.StreamReducer {
+	return mr.Input.Stream(mr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (mr *MaxReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(mr.intervals(), &maxAggregator{})
+}
+
+// maxAggregator holds the state of the interval currently being reduced.
+type maxAggregator struct {
+	max float64
+	set bool
+}
+
+func (a *maxAggregator) Add(point datapoint.TimePoint) {
+	// NaN values, reduced under the propagate NaN policy, make the maximum NaN
+	if !a.set || point.Value > a.max || math.IsNaN(point.Value) {
+		a.max = point.Value
 	}
-	return reduced, nil
+	a.set = true
+}
+
+func (a *maxAggregator) Value() float64 {
+	a.set = false
+	return a.max
 }
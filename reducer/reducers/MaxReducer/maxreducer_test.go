@@ -1,9 +1,12 @@
 package maxreducer
 
 import (
+	"errors"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/EcoPowerHub/dustbuster/reducer"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 	"github.com/stretchr/testify/assert"
 )
@@ -61,6 +64,58 @@ func TestMaxReducer_Reduce(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:     "gap of several intervals",
+			interval: "1m",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 10},
+				{Timestamp: time.Date(2023, 10, 1, 0, 3, 30, 0, time.UTC), Value: 15},
+				{Timestamp: time.Date(2023, 10, 1, 0, 3, 45, 0, time.UTC), Value: 5},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 10},
+				{Timestamp: time.Date(2023, 10, 1, 0, 3, 0, 0, time.UTC), Value: 15},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "negative values only",
+			interval: "1m",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: -10},
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 30, 0, time.UTC), Value: -20},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: -10},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "lowest float values only",
+			interval: "1m",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: -math.MaxFloat64},
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 30, 0, time.UTC), Value: -math.MaxFloat64},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: -math.MaxFloat64},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "infinite values",
+			interval: "1m",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: math.Inf(-1)},
+				{Timestamp: time.Date(2023, 10, 1, 0, 1, 0, 0, time.UTC), Value: 10},
+				{Timestamp: time.Date(2023, 10, 1, 0, 1, 30, 0, time.UTC), Value: math.Inf(1)},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: math.Inf(-1)},
+				{Timestamp: time.Date(2023, 10, 1, 0, 1, 0, 0, time.UTC), Value: math.Inf(1)},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -80,3 +135,69 @@ func TestMaxReducer_Reduce(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxReducer_NewStream(t *testing.T) {
+	mr := &MaxReducer{Interval: time.Minute}
+	stream := mr.NewStream()
+
+	out, err := stream.Push(datapoint.TimePoint{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	out, err = stream.Push(datapoint.TimePoint{Timestamp: time.Date(2023, 10, 1, 0, 1, 0, 0, time.UTC), Value: 15})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 10}}, out)
+
+	_, err = stream.Push(datapoint.TimePoint{Timestamp: time.Date(2023, 10, 1, 0, 0, 30, 0, time.UTC), Value: 20})
+	assert.Error(t, err, "out of order points must be rejected")
+
+	out, err = stream.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Date(2023, 10, 1, 0, 1, 0, 0, time.UTC), Value: 15}}, out)
+}
+
+func TestMaxReducer_NaN(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 10},
+		{Timestamp: time.Date(2023, 10, 1, 0, 0, 30, 0, time.UTC), Value: math.NaN()},
+		{Timestamp: time.Date(2023, 10, 1, 0, 0, 45, 0, time.UTC), Value: 5},
+		{Timestamp: time.Date(2023, 10, 1, 0, 1, 0, 0, time.UTC), Value: math.NaN()},
+	}
+
+	r, err := New(&Configuration{Interval: "1m"})
+	assert.NoError(t, err)
+	result, err := r.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: data[0].Timestamp, Value: 10}}, result, "NaN values are skipped by default")
+	result, err = reducer.ReduceStream(r.(reducer.Streamer).NewStream(), data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: data[0].Timestamp, Value: 10}}, result, "streams skip NaN values as well")
+	_, err = r.Reduce(data[3:])
+	assert.ErrorIs(t, err, reducer.ErrNoData)
+
+	r, err = New(&Configuration{Interval: "1m", InputOptions: reducer.InputOptions{NaN: reducer.NaNPropagate}})
+	assert.NoError(t, err)
+	result, err = r.Reduce(data)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2, "NaN values make the maximum of their interval NaN")
+	for _, point := range result {
+		assert.True(t, math.IsNaN(point.Value))
+	}
+}
+
+func TestMaxReducer_ErrorIndex(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(60, 0), Value: 5},
+		{Timestamp: time.Unix(30, 0), Value: 3},
+	}
+
+	r, err := New(&Configuration{Interval: "1m", InputOptions: reducer.InputOptions{Order: reducer.OrderTrust}})
+	assert.NoError(t, err)
+	_, err = r.Reduce(data)
+	assert.ErrorIs(t, err, reducer.ErrUnsorted)
+	var e *reducer.Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 2, e.Index, "the index refers to the input data, NaN values included")
+	}
+}
@@ -1,17 +1,17 @@
 package minreducer
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"math"
 	"time"
 
 	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 )
 
 // New creates a new instance of MinReducer with the provided configuration.
-// It parses the interval duration from the configuration and returns an error if the interval is invalid.
+// It parses the interval, either a duration or a calendar interval, and returns an error if it is invalid.
 //
 // Parameters:
 //   - conf: Configuration struct containing the interval as a string.
@@ -20,18 +20,27 @@ import (
 //   - *MinReducer: A pointer to the newly created MinReducer instance.
 //   - error: An error if the interval parsing fails.
 func New(conf *Configuration) (reducer.DataReducer, error) {
-	interval, err := time.ParseDuration(conf.Interval)
+	spec, err := interval.Parse(conf.Interval, conf.Options)
 	if err != nil {
-		return nil, fmt.Errorf("invalid interval: %w", err)
+		return nil, err
 	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
 	return &MinReducer{
-		Interval: interval,
+		Interval: spec.Duration(),
+		Input:    input,
+		spec:     spec,
 	}, nil
 }
 
 // MinReducer reduces data by keeping the minimum value over fixed intervals.
 type MinReducer struct {
-	Interval time.Duration
+	Interval time.Duration // Length of fixed intervals, zero for calendar intervals
+	Input    reducer.Input
+	spec     interval.Spec // Intervals parsed by New, along with their options
 }
 
 // Reduce processes a slice of TimePoint data and reduces it by finding the minimum value
@@ -46,37 +55,53 @@ type MinReducer struct {
 //   - An error if the input data slice is empty.
 //
 // The function iterates over the input data and groups the points by the specified interval.
-// For each interval, it finds the minimum value and appends a new TimePoint with the start
-// time of the interval and the minimum value to the result slice.
+// For each interval, it finds the minimum value and appends a new TimePoint with the label
+// of the interval and the minimum value to the result slice. Empty intervals follow the
+// gaps option.
 func (mr *MinReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
-	if len(data) == 0 {
-		return nil, errors.New("no data to reduce")
-	}
-	var reduced []datapoint.TimePoint
-	startTime := data[0].Timestamp
-	minValue := math.MaxFloat64
+	return mr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (mr *MinReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return mr.Input.Reduce(ctx, mr.newStream(), data)
+}
 
-	for _, point := range data {
-		if point.Timestamp.Before(startTime.Add(mr.Interval)) {
-			if point.Value < minValue {
-				minValue = point.Value
-			}
-		} else {
-			reduced = append(reduced, datapoint.TimePoint{
-				Timestamp: startTime,
-				Value:     minValue,
-			})
-			startTime = startTime.Add(mr.Interval)
-			minValue = point.Value
-		}
+// intervals returns the intervals parsed by New, or the fixed intervals of
+// Interval when the reducer was built as a struct literal.
+func (mr *MinReducer) intervals() interval.Spec {
+	if !mr.spec.IsZero() {
+		return mr.spec
 	}
+	return interval.Fixed(mr.Interval)
+}
 
-	if minValue < math.MaxFloat64 {
-		reduced = append(reduced, datapoint.TimePoint{
-			Timestamp: startTime,
-			Value:     minValue,
-		})
+// NewStream returns a StreamReducer keeping the minimum of the pushed points over the reducer's interval.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (mr *MinReducer) NewStream() reducer.StreamReducer {
+	return mr.Input.Stream(mr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (mr *MinReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(mr.intervals(), &minAggregator{})
+}
+
+// minAggregator holds the state of the interval currently being reduced.
+type minAggregator struct {
+	min float64
+	set bool
+}
+
+func (a *minAggregator) Add(point datapoint.TimePoint) {
+	// NaN values, reduced under the propagate NaN policy, make the minimum NaN
+	if !a.set || point.Value < a.min || math.IsNaN(point.Value) {
+		a.min = point.Value
 	}
+	a.set = true
+}
 
-	return reduced, nil
+func (a *minAggregator) Value() float64 {
+	a.set = false
+	return a.min
 }
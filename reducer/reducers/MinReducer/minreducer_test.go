@@ -1,9 +1,12 @@
 package minreducer
 
 import (
+	"math"
 	"testing"
 	"time"
 
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,6 +21,24 @@ func TestNew(t *testing.T) {
 			name: "valid interval",
 			conf: Configuration{Interval: "1m"},
 		},
+		{
+			name: "epoch aligned interval",
+			conf: Configuration{Interval: "15m", Options: interval.Options{Align: interval.AlignEpoch}},
+		},
+		{
+			name: "interval ending label",
+			conf: Configuration{Interval: "15m", Options: interval.Options{Label: interval.LabelEnd}},
+		},
+		{
+			name:      "invalid label",
+			conf:      Configuration{Interval: "15m", Options: interval.Options{Label: "invalid"}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid alignment",
+			conf:      Configuration{Interval: "15m", Options: interval.Options{Align: "invalid"}},
+			expectErr: true,
+		},
 		{
 			name:      "invalid interval",
 			conf:      Configuration{Interval: "invalid"},
@@ -103,6 +124,30 @@ func TestMinReducer_Reduce(t *testing.T) {
 				{Timestamp: time.Unix(0, 0), Value: 42},
 			},
 		},
+		{
+			name:     "highest float values only",
+			interval: "1m",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: math.MaxFloat64},
+				{Timestamp: time.Unix(30, 0), Value: math.MaxFloat64},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: math.MaxFloat64},
+			},
+		},
+		{
+			name:     "infinite values",
+			interval: "1m",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: math.Inf(1)},
+				{Timestamp: time.Unix(60, 0), Value: -10},
+				{Timestamp: time.Unix(90, 0), Value: math.Inf(-1)},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: math.Inf(1)},
+				{Timestamp: time.Unix(60, 0), Value: math.Inf(-1)},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -119,3 +164,54 @@ func TestMinReducer_Reduce(t *testing.T) {
 		})
 	}
 }
+
+func TestMinReducer_NewStream(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 10},
+		{Timestamp: time.Unix(30, 0), Value: 5},
+		{Timestamp: time.Unix(60, 0), Value: 20},
+		{Timestamp: time.Unix(120, 0), Value: 15},
+	}
+	mr := &MinReducer{Interval: time.Minute}
+	stream := mr.NewStream()
+
+	var streamed []datapoint.TimePoint
+	for _, point := range data {
+		out, err := stream.Push(point)
+		assert.NoError(t, err)
+		streamed = append(streamed, out...)
+	}
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 5},
+		{Timestamp: time.Unix(60, 0), Value: 20},
+	}, streamed)
+
+	out, err := stream.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(120, 0), Value: 15}}, out)
+
+	reduced, err := mr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, append(streamed, out...), reduced)
+}
+
+func TestMinReducer_NaN(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 10},
+		{Timestamp: time.Unix(30, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(45, 0), Value: 5},
+	}
+
+	mr, err := New(&Configuration{Interval: "1m"})
+	assert.NoError(t, err)
+	result, err := mr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 5}}, result, "NaN values are skipped by default")
+
+	mr, err = New(&Configuration{Interval: "1m", InputOptions: reducer.InputOptions{NaN: reducer.NaNPropagate}})
+	assert.NoError(t, err)
+	result, err = mr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.True(t, math.IsNaN(result[0].Value), "NaN values make the minimum of their interval NaN")
+}
@@ -0,0 +1,22 @@
+package quantilereducer
+
+import (
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+)
+
+// Configuration holds the interval and the quantiles (between 0 and 1) to
+// compute over each interval. Method is "exact", "sketch" or "auto" (the
+// default), which computes exact quantiles for intervals holding up to
+// ExactLimit points (1024 by default) and switches to a t-digest sketch of the
+// given Compression (100 by default) for larger ones.
+type Configuration struct {
+	Interval    string    `json:"interval" required:"true" description:"Length of the intervals: a duration (15m) or a calendar interval (1d, 1w, 1mo, 1y)"`
+	Quantiles   []float64 `json:"quantiles" required:"true" description:"Quantiles to compute, between 0 and 1"`
+	Method      string    `json:"method" default:"auto" enum:"exact,sketch,auto" description:"Exact quantiles, t-digest sketch, or exact up to exact_limit points"`
+	Compression float64   `json:"compression" default:"100" minimum:"1" description:"Compression of the t-digest sketch"`
+	ExactLimit  int       `json:"exact_limit" default:"1024" minimum:"0" description:"Number of points above which the auto method sketches an interval"`
+
+	interval.Options     `json:",squash"`
+	reducer.InputOptions `json:",squash"`
+}
@@ -0,0 +1,243 @@
+package quantilereducer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+const (
+	// MethodExact keeps every value of an interval and computes exact quantiles.
+	MethodExact = "exact"
+	// MethodSketch estimates quantiles with a t-digest of bounded memory.
+	MethodSketch = "sketch"
+	// MethodAuto computes exact quantiles for small intervals and sketches large ones.
+	MethodAuto = "auto"
+
+	defaultCompression = 100
+	defaultExactLimit  = 1024
+)
+
+// New creates a new instance of QuantileReducer with the provided configuration.
+// It parses the interval and validates the quantiles and the method.
+//
+// Parameters:
+//   - conf: Configuration struct containing the interval, the quantiles and the method.
+//
+// Returns:
+//   - *QuantileReducer: A pointer to the newly created QuantileReducer instance.
+//   - error: An error if any of the configuration values is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(conf.Quantiles) == 0 {
+		return nil, fmt.Errorf("%w: at least one quantile is required", reducer.ErrInvalidConfiguration)
+	}
+	for _, q := range conf.Quantiles {
+		if q < 0 || q > 1 || math.IsNaN(q) {
+			return nil, fmt.Errorf("%w: quantile must be between 0 and 1, got %v", reducer.ErrInvalidConfiguration, q)
+		}
+	}
+
+	method := conf.Method
+	switch method {
+	case "":
+		method = MethodAuto
+	case MethodExact, MethodSketch, MethodAuto:
+	default:
+		return nil, fmt.Errorf("%w: invalid method: %q", reducer.ErrInvalidConfiguration, conf.Method)
+	}
+
+	compression := conf.Compression
+	if compression == 0 {
+		compression = defaultCompression
+	}
+	if compression < 1 {
+		return nil, fmt.Errorf("%w: compression must be at least 1, got %v", reducer.ErrInvalidConfiguration, compression)
+	}
+	exactLimit := conf.ExactLimit
+	if exactLimit == 0 {
+		exactLimit = defaultExactLimit
+	}
+	if exactLimit < 0 {
+		return nil, fmt.Errorf("%w: exact limit must not be negative, got %d", reducer.ErrInvalidConfiguration, exactLimit)
+	}
+
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuantileReducer{
+		Interval:    spec,
+		Quantiles:   append([]float64(nil), conf.Quantiles...),
+		Method:      method,
+		Compression: compression,
+		ExactLimit:  exactLimit,
+		Input:       input,
+	}, nil
+}
+
+// QuantileReducer reduces data by computing quantiles (median, p95, p99...)
+// over fixed intervals.
+//
+// As a DataReducer it returns the series of the first configured quantile;
+// ReduceMulti returns one series per configured quantile.
+type QuantileReducer struct {
+	Interval    interval.Spec
+	Quantiles   []float64
+	Method      string
+	Compression float64
+	ExactLimit  int
+	Input       reducer.Input
+}
+
+// Reduce returns the series of the first configured quantile over each interval.
+// See ReduceMulti for the details.
+func (qr *QuantileReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return qr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (qr *QuantileReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	reduced, err := qr.ReduceMultiContext(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	return reduced[0], nil
+}
+
+// ReduceMulti returns one series per configured quantile, in the order of the
+// configuration. Each series holds a point per interval holding data, stamped
+// with the interval label, the first interval holding the first point. Empty
+// intervals follow the gaps option.
+// Exact quantiles are linearly interpolated between the closest ranks.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (qr *QuantileReducer) ReduceMulti(data []datapoint.TimePoint) ([][]datapoint.TimePoint, error) {
+	return qr.ReduceMultiContext(context.Background(), data)
+}
+
+// ReduceMultiContext behaves like ReduceMulti, but stops with an error wrapping ctx.Err() once ctx is done.
+func (qr *QuantileReducer) ReduceMultiContext(ctx context.Context, data []datapoint.TimePoint) ([][]datapoint.TimePoint, error) {
+	if len(data) == 0 {
+		return nil, reducer.ErrNoData
+	}
+	if err := qr.Interval.Validate(); err != nil {
+		return nil, err
+	}
+	prepared, err := qr.Input.Prepare(data)
+	if err != nil {
+		return nil, err
+	}
+	// Indices in prepared only refer to data when no point was dropped or merged
+	unknownIndex := len(prepared) != len(data)
+	data = prepared
+
+	reduced := make([][]datapoint.TimePoint, len(qr.Quantiles))
+	emitters := make([]*interval.Emitter, len(qr.Quantiles))
+	for i := range emitters {
+		emitters[i] = interval.NewEmitter(qr.Interval)
+	}
+	b := qr.newBucket()
+	startTime := qr.Interval.Start(data[0].Timestamp)
+	endTime := qr.Interval.Next(startTime)
+	for i, point := range data {
+		if err := reducer.CheckContext(ctx, i); err != nil {
+			return nil, err
+		}
+		if i > 0 && point.Timestamp.Before(data[i-1].Timestamp) {
+			index := i
+			if unknownIndex {
+				index = -1
+			}
+			return nil, &reducer.Error{Index: index, Timestamp: point.Timestamp, Err: reducer.ErrUnsorted}
+		}
+		if !point.Timestamp.Before(endTime) {
+			qr.emit(reduced, emitters, startTime, b)
+			b = qr.newBucket()
+			startTime = qr.Interval.Advance(startTime, point.Timestamp)
+			endTime = qr.Interval.Next(startTime)
+		}
+		b.add(point.Value)
+	}
+	qr.emit(reduced, emitters, startTime, b)
+
+	return reduced, nil
+}
+
+// emit appends the quantiles of the bucket to their series.
+func (qr *QuantileReducer) emit(reduced [][]datapoint.TimePoint, emitters []*interval.Emitter, startTime time.Time, b *bucket) {
+	for i, q := range qr.Quantiles {
+		reduced[i] = append(reduced[i], emitters[i].Emit(startTime, b.quantile(q))...)
+	}
+}
+
+func (qr *QuantileReducer) newBucket() *bucket {
+	b := &bucket{compression: qr.Compression, exactLimit: -1}
+	switch qr.Method {
+	case MethodSketch:
+		b.digest = newTDigest(qr.Compression)
+	case MethodAuto:
+		b.exactLimit = qr.ExactLimit
+	}
+	return b
+}
+
+// bucket holds the values of an interval, either as is or as a t-digest once
+// the exact limit has been exceeded.
+type bucket struct {
+	compression float64
+	exactLimit  int // Negative when values are never sketched
+	values      []float64
+	sorted      bool
+	digest      *tdigest
+	nan         bool // Whether a NaN value was added, under the propagate NaN policy
+}
+
+func (b *bucket) add(value float64) {
+	if math.IsNaN(value) {
+		b.nan = true
+		return
+	}
+	if b.digest != nil {
+		b.digest.Add(value)
+		return
+	}
+	b.values = append(b.values, value)
+	b.sorted = false
+	if b.exactLimit >= 0 && len(b.values) > b.exactLimit {
+		b.digest = newTDigest(b.compression)
+		for _, v := range b.values {
+			b.digest.Add(v)
+		}
+		b.values = nil
+	}
+}
+
+func (b *bucket) quantile(q float64) float64 {
+	if b.nan {
+		return math.NaN()
+	}
+	if b.digest != nil {
+		return b.digest.Quantile(q)
+	}
+	if !b.sorted {
+		sort.Float64s(b.values)
+		b.sorted = true
+	}
+	rank := q * float64(len(b.values)-1)
+	lower := int(math.Floor(rank))
+	if lower+1 >= len(b.values) {
+		return b.values[lower]
+	}
+	return b.values[lower] + (rank-float64(lower))*(b.values[lower+1]-b.values[lower])
+}
@@ -0,0 +1,150 @@
+package quantilereducer
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expectErr bool
+	}{
+		{
+			name: "valid configuration",
+			conf: Configuration{Interval: "1m", Quantiles: []float64{0.5, 0.95, 0.99}},
+		},
+		{
+			name:      "invalid interval",
+			conf:      Configuration{Interval: "invalid", Quantiles: []float64{0.5}},
+			expectErr: true,
+		},
+		{
+			name:      "missing quantiles",
+			conf:      Configuration{Interval: "1m"},
+			expectErr: true,
+		},
+		{
+			name:      "quantile out of range",
+			conf:      Configuration{Interval: "1m", Quantiles: []float64{95}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid method",
+			conf:      Configuration{Interval: "1m", Quantiles: []float64{0.5}, Method: "approx"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReduceMulti(t *testing.T) {
+	qr, err := New(&Configuration{Interval: "1m", Quantiles: []float64{0.5, 0.9, 0}, Method: MethodExact})
+	assert.NoError(t, err)
+
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 4},
+		{Timestamp: time.Unix(10, 0), Value: 1},
+		{Timestamp: time.Unix(20, 0), Value: 3},
+		{Timestamp: time.Unix(30, 0), Value: 2},
+		{Timestamp: time.Unix(180, 0), Value: 7},
+	}
+	reduced, err := qr.(*QuantileReducer).ReduceMulti(data)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]datapoint.TimePoint{
+		{
+			{Timestamp: time.Unix(0, 0), Value: 2.5},
+			{Timestamp: time.Unix(180, 0), Value: 7},
+		},
+		{
+			{Timestamp: time.Unix(0, 0), Value: 3.7},
+			{Timestamp: time.Unix(180, 0), Value: 7},
+		},
+		{
+			{Timestamp: time.Unix(0, 0), Value: 1},
+			{Timestamp: time.Unix(180, 0), Value: 7},
+		},
+	}, reduced)
+
+	// Reduce returns the series of the first quantile
+	median, err := qr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, reduced[0], median)
+
+	_, err = qr.Reduce([]datapoint.TimePoint{})
+	assert.Error(t, err)
+}
+
+func TestReduce_UnsortedIndex(t *testing.T) {
+	qr, err := New(&Configuration{Interval: "1m", Quantiles: []float64{0.5}, InputOptions: reducer.InputOptions{Order: reducer.OrderTrust}})
+	assert.NoError(t, err)
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(60, 0), Value: 2},
+		{Timestamp: time.Unix(30, 0), Value: 3},
+	}
+	_, err = qr.Reduce(data)
+	var e *reducer.Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, 2, e.Index)
+	}
+
+	// Once a NaN value is dropped, the index in the input data is unknown
+	_, err = qr.Reduce(append([]datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: math.NaN()}}, data...))
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, -1, e.Index)
+		assert.Equal(t, time.Unix(30, 0), e.Timestamp)
+	}
+}
+
+func TestReduce_Sketch(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	data := make([]datapoint.TimePoint, 100000)
+	for i := range data {
+		data[i] = datapoint.TimePoint{
+			Timestamp: time.Unix(0, int64(i)*int64(time.Millisecond)),
+			Value:     random.Float64() * 100,
+		}
+	}
+
+	for _, method := range []string{MethodSketch, MethodAuto} {
+		t.Run(method, func(t *testing.T) {
+			qr, err := New(&Configuration{Interval: "1h", Quantiles: []float64{0.5, 0.99}, Method: method})
+			assert.NoError(t, err)
+			reduced, err := qr.(*QuantileReducer).ReduceMulti(data)
+			assert.NoError(t, err)
+			assert.Len(t, reduced[0], 1)
+			assert.InDelta(t, 50, reduced[0][0].Value, 1)
+			assert.InDelta(t, 99, reduced[1][0].Value, 0.2)
+		})
+	}
+}
+
+func TestTDigest_Bounded(t *testing.T) {
+	d := newTDigest(50)
+	for i := 0; i < 200000; i++ {
+		d.Add(float64(i))
+	}
+	d.merge()
+	assert.LessOrEqual(t, len(d.centroids), 50)
+	assert.Equal(t, float64(0), d.Quantile(0))
+	assert.Equal(t, float64(199999), d.Quantile(1))
+}
@@ -0,0 +1,129 @@
+package quantilereducer
+
+import (
+	"math"
+	"sort"
+)
+
+// centroid is a cluster of values of the t-digest, summarized by their mean.
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// tdigest is a merging t-digest: a sketch estimating quantiles with a memory
+// footprint bounded by its compression, whatever the number of values added.
+// The estimation is most accurate close to the extreme quantiles.
+type tdigest struct {
+	compression float64
+	centroids   []centroid // Sorted by mean
+	buffer      []centroid // Values added since the last merge
+	count       float64
+	min         float64
+	max         float64
+}
+
+func newTDigest(compression float64) *tdigest {
+	return &tdigest{
+		compression: compression,
+		buffer:      make([]centroid, 0, bufferSize(compression)),
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// bufferSize returns the number of values buffered between two merges.
+func bufferSize(compression float64) int {
+	return int(5*compression) + 1
+}
+
+// Add adds a value to the digest.
+func (d *tdigest) Add(value float64) {
+	d.buffer = append(d.buffer, centroid{mean: value, weight: 1})
+	d.count++
+	d.min = math.Min(d.min, value)
+	d.max = math.Max(d.max, value)
+	if len(d.buffer) >= cap(d.buffer) {
+		d.merge()
+	}
+}
+
+// merge folds the buffered values into the centroids. Neighbouring centroids
+// are merged as long as they span less than one unit of the k1 scale function,
+// which bounds the number of centroids to about compression/2 while keeping
+// small centroids close to the extreme quantiles.
+func (d *tdigest) merge() {
+	if len(d.buffer) == 0 {
+		return
+	}
+	all := append(d.buffer, d.centroids...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].mean < all[j].mean
+	})
+
+	merged := make([]centroid, 0, len(d.centroids)+1)
+	current := all[0]
+	cumulative := 0.0
+	limit := d.quantileLimit(0)
+	for _, c := range all[1:] {
+		proposed := current.weight + c.weight
+		if (cumulative+proposed)/d.count <= limit {
+			current.mean += (c.mean - current.mean) * c.weight / proposed
+			current.weight = proposed
+			continue
+		}
+		cumulative += current.weight
+		merged = append(merged, current)
+		limit = d.quantileLimit(cumulative / d.count)
+		current = c
+	}
+	d.centroids = append(merged, current)
+	d.buffer = d.buffer[:0]
+}
+
+// quantileLimit returns the largest quantile a centroid starting at q may reach,
+// one unit further on the k1 scale function k(q) = compression/(2π)·asin(2q-1).
+func (d *tdigest) quantileLimit(q float64) float64 {
+	k := d.compression/(2*math.Pi)*math.Asin(2*q-1) + 1
+	if k >= d.compression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/d.compression) + 1) / 2
+}
+
+// Quantile returns an estimation of the q-quantile of the values added.
+func (d *tdigest) Quantile(q float64) float64 {
+	d.merge()
+	if d.count == 0 {
+		return math.NaN()
+	}
+	if q <= 0 || len(d.centroids) == 1 && d.centroids[0].weight == 1 {
+		return d.min
+	}
+	if q >= 1 {
+		return d.max
+	}
+
+	// Each centroid is centered on the middle of its weight; interpolate
+	// between the centers surrounding the target rank, or the extremes.
+	target := q * d.count
+	previousRank, previousMean := 0.0, d.min
+	cumulative := 0.0
+	for _, c := range d.centroids {
+		rank := cumulative + c.weight/2
+		if target < rank {
+			return interpolate(previousRank, previousMean, rank, c.mean, target)
+		}
+		previousRank, previousMean = rank, c.mean
+		cumulative += c.weight
+	}
+	return interpolate(previousRank, previousMean, d.count, d.max, target)
+}
+
+// interpolate returns the value at x on the line going through (x0, y0) and (x1, y1).
+func interpolate(x0, y0, x1, y1, x float64) float64 {
+	if x1 <= x0 {
+		return y1
+	}
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
@@ -0,0 +1,88 @@
+package rangereducer
+
+import (
+	"context"
+	"math"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// New creates a new instance of RangeReducer with the provided configuration.
+// It parses the interval, either a duration or a calendar interval, and returns an error if it is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RangeReducer{
+		Interval: spec,
+		Input:    input,
+	}, nil
+}
+
+// RangeReducer reduces data by computing the range (maximum minus minimum) over fixed intervals.
+type RangeReducer struct {
+	Interval interval.Spec
+	Input    reducer.Input
+}
+
+// Reduce takes a slice of TimePoint data and returns, for each interval holding
+// data, the difference between the maximum and the minimum value, stamped with the
+// interval label. The first interval holds the first point and empty intervals
+// follow the gaps option. An interval whose extremes are the same infinity has a
+// zero range.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (rr *RangeReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return rr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (rr *RangeReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return rr.Input.Reduce(ctx, rr.newStream(), data)
+}
+
+// NewStream returns a StreamReducer computing the range (maximum minus minimum) of
+// the pushed points over the reducer's interval.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (rr *RangeReducer) NewStream() reducer.StreamReducer {
+	return rr.Input.Stream(rr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (rr *RangeReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(rr.Interval, &rangeAggregator{})
+}
+
+// rangeAggregator holds the state of the interval currently being reduced.
+type rangeAggregator struct {
+	min float64
+	max float64
+	set bool
+}
+
+func (a *rangeAggregator) Add(point datapoint.TimePoint) {
+	// NaN values, reduced under the propagate NaN policy, make the range NaN
+	if !a.set || point.Value < a.min || math.IsNaN(point.Value) {
+		a.min = point.Value
+	}
+	if !a.set || point.Value > a.max || math.IsNaN(point.Value) {
+		a.max = point.Value
+	}
+	a.set = true
+}
+
+func (a *rangeAggregator) Value() float64 {
+	a.set = false
+	if a.max == a.min {
+		// Equal infinite extremes would otherwise make the range NaN
+		return 0
+	}
+	return a.max - a.min
+}
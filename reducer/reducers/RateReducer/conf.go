@@ -0,0 +1,22 @@
+package ratereducer
+
+import (
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+)
+
+// Configuration holds the rate settings. Without an interval the derivative is
+// computed between each pair of consecutive samples; with an interval, Mode
+// selects "rate" (average rate over the interval, the default) or "irate"
+// (rate between the last two samples of the interval). Unit is the time unit
+// of the rate and defaults to "1s".
+type Configuration struct {
+	Interval    string `json:"interval" description:"Length of the intervals: a duration (15m) or a calendar interval (1d, 1w, 1mo, 1y), empty for the derivative between consecutive samples"`
+	Mode        string `json:"mode" default:"rate" enum:"rate,irate" description:"Average rate over the interval or rate between its last two samples"`
+	Unit        string `json:"unit" default:"1s" description:"Time unit of the rate"`
+	Counter     bool   `json:"counter" description:"Treat decreases as counter resets"`
+	NonNegative bool   `json:"non_negative" description:"Drop negative rates"`
+
+	interval.Options     `json:",squash"`
+	reducer.InputOptions `json:",squash"`
+}
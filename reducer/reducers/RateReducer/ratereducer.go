@@ -0,0 +1,233 @@
+package ratereducer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+const (
+	// ModeRate computes the average rate over each interval.
+	ModeRate = "rate"
+	// ModeIRate computes the instant rate between the last two samples of each interval.
+	ModeIRate = "irate"
+)
+
+// New creates a new instance of RateReducer with the provided configuration.
+// It parses the optional interval, the unit and validates the mode.
+//
+// Parameters:
+//   - conf: Configuration struct containing the rate settings.
+//
+// Returns:
+//   - *RateReducer: A pointer to the newly created RateReducer instance.
+//   - error: An error if any of the configuration values is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	var spec interval.Spec
+	if conf.Interval != "" {
+		var err error
+		spec, err = interval.Parse(conf.Interval, conf.Options)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Rates between consecutive samples are stamped with the later one
+		if err := conf.Options.RejectGaps(); err != nil {
+			return nil, err
+		}
+		if err := conf.Options.RejectLabel(); err != nil {
+			return nil, err
+		}
+	}
+
+	mode := conf.Mode
+	switch mode {
+	case "":
+		mode = ModeRate
+	case ModeRate, ModeIRate:
+	default:
+		return nil, fmt.Errorf("%w: invalid mode: %q", reducer.ErrInvalidConfiguration, conf.Mode)
+	}
+
+	unit := time.Second
+	if conf.Unit != "" {
+		var err error
+		unit, err = time.ParseDuration(conf.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid unit: %w", reducer.ErrInvalidConfiguration, err)
+		}
+		if unit <= 0 {
+			return nil, fmt.Errorf("%w: unit must be positive, got %v", reducer.ErrInvalidConfiguration, unit)
+		}
+	}
+
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RateReducer{
+		Interval:    spec,
+		Mode:        mode,
+		Unit:        unit,
+		Counter:     conf.Counter,
+		NonNegative: conf.NonNegative,
+		Input:       input,
+	}, nil
+}
+
+// RateReducer reduces data to its rate of change per Unit of time, in the
+// spirit of Prometheus' rate and irate functions (without extrapolation).
+type RateReducer struct {
+	Interval    interval.Spec // Zero for the derivative between consecutive samples
+	Mode        string
+	Unit        time.Duration
+	Counter     bool // Treat decreases as counter resets to zero
+	NonNegative bool // Drop negative rates
+	Input       reducer.Input
+}
+
+// Reduce takes a slice of TimePoint data and returns its rate of change.
+//
+// Without an interval, a rate is emitted for each pair of consecutive samples,
+// stamped with the later one. With an interval, a rate is emitted for each
+// interval holding at least two samples, stamped with the interval label, the
+// first interval holding the first point. Only pairs of samples within the same
+// interval are taken into account, and intervals without a rate follow the gaps
+// option. In both modes, pairs of samples sharing a timestamp are skipped.
+// Under the NaNGap policy, pairs of samples involving a NaN value are skipped.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (rr *RateReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return rr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (rr *RateReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return rr.Input.ReduceSignal(ctx, rr.newStream(), data)
+}
+
+// NewStream returns a StreamReducer computing the rate of the pushed points.
+// Pushing a point older than the previous one returns an error.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (rr *RateReducer) NewStream() reducer.StreamReducer {
+	return rr.Input.StreamSignal(rr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (rr *RateReducer) newStream() reducer.StreamReducer {
+	return &rateStream{reducer: rr, emitter: interval.NewEmitter(rr.Interval)}
+}
+
+// rateStream holds the previous point and the deltas of the interval currently being reduced.
+type rateStream struct {
+	reducer   *RateReducer
+	emitter   *interval.Emitter
+	started   bool
+	previous  datapoint.TimePoint
+	startTime time.Time
+	pairs     int
+	delta     float64       // Sum of the deltas within the interval
+	span      time.Duration // Time covered by the pairs within the interval
+	lastDelta float64
+	lastSpan  time.Duration
+	index     int // Index of the next pushed point
+}
+
+func (s *rateStream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	index := s.index
+	s.index++
+	if !s.started {
+		if !s.reducer.Interval.IsZero() {
+			if err := s.reducer.Interval.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		s.started = true
+		s.startTime = s.reducer.Interval.Start(point.Timestamp)
+		s.previous = point
+		return nil, nil
+	}
+	if point.Timestamp.Before(s.previous.Timestamp) {
+		return nil, &reducer.Error{Index: index, Timestamp: point.Timestamp, Err: reducer.ErrUnsorted}
+	}
+	previous := s.previous
+	s.previous = point
+	span := point.Timestamp.Sub(previous.Timestamp)
+	delta := s.deltaBetween(previous.Value, point.Value)
+	gap := s.reducer.Input.NaN == reducer.NaNGap && math.IsNaN(delta)
+
+	if s.reducer.Interval.IsZero() {
+		if span == 0 || gap {
+			return nil, nil
+		}
+		value, ok := s.rate(delta, span)
+		if !ok {
+			return nil, nil
+		}
+		return []datapoint.TimePoint{{Timestamp: point.Timestamp, Value: value}}, nil
+	}
+
+	if point.Timestamp.Before(s.reducer.Interval.Next(s.startTime)) {
+		if span == 0 || gap {
+			return nil, nil
+		}
+		s.pairs++
+		s.delta += delta
+		s.span += span
+		s.lastDelta = delta
+		s.lastSpan = span
+		return nil, nil
+	}
+
+	reduced := s.emit()
+	s.startTime = s.reducer.Interval.Advance(s.startTime, point.Timestamp)
+	return reduced, nil
+}
+
+// Flush returns the rate of the last interval.
+func (s *rateStream) Flush() ([]datapoint.TimePoint, error) {
+	if !s.started || s.reducer.Interval.IsZero() {
+		return nil, nil
+	}
+	s.started = false
+	return s.emit(), nil
+}
+
+// emit returns the rate of the current interval, if it holds enough samples, and resets it.
+func (s *rateStream) emit() []datapoint.TimePoint {
+	pairs, delta, span := s.pairs, s.delta, s.span
+	if s.reducer.Mode == ModeIRate {
+		delta, span = s.lastDelta, s.lastSpan
+	}
+	s.pairs, s.delta, s.span = 0, 0, 0
+	if pairs == 0 || span == 0 {
+		return nil
+	}
+	value, ok := s.rate(delta, span)
+	if !ok {
+		return nil
+	}
+	return s.emitter.Emit(s.startTime, value)
+}
+
+// rate returns delta per Unit over span, and false when it must be dropped.
+func (s *rateStream) rate(delta float64, span time.Duration) (float64, bool) {
+	value := delta * float64(s.reducer.Unit) / float64(span)
+	if s.reducer.NonNegative && value < 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+// deltaBetween returns the change between two samples, a decrease of a counter being a reset to zero.
+func (s *rateStream) deltaBetween(previous, current float64) float64 {
+	if s.reducer.Counter && current < previous {
+		return current
+	}
+	return current - previous
+}
@@ -0,0 +1,174 @@
+package ratereducer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expectErr bool
+	}{
+		{
+			name: "defaults",
+			conf: Configuration{},
+		},
+		{
+			name: "irate per minute",
+			conf: Configuration{Interval: "5m", Mode: ModeIRate, Unit: "1m"},
+		},
+		{
+			name:      "invalid interval",
+			conf:      Configuration{Interval: "invalid"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid mode",
+			conf:      Configuration{Mode: "delta"},
+			expectErr: true,
+		},
+		{
+			name:      "gaps without interval",
+			conf:      Configuration{Options: interval.Options{Gaps: interval.GapsZero}},
+			expectErr: true,
+		},
+		{
+			name: "gaps with interval",
+			conf: Configuration{Interval: "1m", Options: interval.Options{Gaps: interval.GapsZero}},
+		},
+		{
+			name:      "invalid unit",
+			conf:      Configuration{Unit: "0s"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	counter := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 0},
+		{Timestamp: time.Unix(10, 0), Value: 20},
+		{Timestamp: time.Unix(20, 0), Value: 30},
+		{Timestamp: time.Unix(30, 0), Value: 5},
+		{Timestamp: time.Unix(60, 0), Value: 35},
+		{Timestamp: time.Unix(90, 0), Value: 95},
+	}
+	duplicated := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 0},
+		{Timestamp: time.Unix(10, 0), Value: 10},
+		{Timestamp: time.Unix(20, 0), Value: 10},
+		{Timestamp: time.Unix(20, 0), Value: 30},
+	}
+
+	tests := []struct {
+		name      string
+		conf      Configuration
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		expectErr bool
+	}{
+		{
+			name:      "empty data",
+			conf:      Configuration{},
+			data:      []datapoint.TimePoint{},
+			expectErr: true,
+		},
+		{
+			name: "gauge derivative",
+			conf: Configuration{},
+			data: counter,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(10, 0), Value: 2},
+				{Timestamp: time.Unix(20, 0), Value: 1},
+				{Timestamp: time.Unix(30, 0), Value: -2.5},
+				{Timestamp: time.Unix(60, 0), Value: 1},
+				{Timestamp: time.Unix(90, 0), Value: 2},
+			},
+		},
+		{
+			name: "non negative derivative per minute",
+			conf: Configuration{Unit: "1m", NonNegative: true},
+			data: counter,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(10, 0), Value: 120},
+				{Timestamp: time.Unix(20, 0), Value: 60},
+				{Timestamp: time.Unix(60, 0), Value: 60},
+				{Timestamp: time.Unix(90, 0), Value: 120},
+			},
+		},
+		{
+			name: "counter rate over intervals",
+			conf: Configuration{Interval: "1m", Counter: true},
+			data: counter,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 35.0 / 30},
+				{Timestamp: time.Unix(60, 0), Value: 2},
+			},
+		},
+		{
+			name: "counter irate over intervals",
+			conf: Configuration{Interval: "1m", Mode: ModeIRate, Counter: true},
+			data: counter,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0.5},
+				{Timestamp: time.Unix(60, 0), Value: 2},
+			},
+		},
+		{
+			name: "irate skips samples sharing a timestamp",
+			conf: Configuration{Interval: "1m", Mode: ModeIRate},
+			data: duplicated,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0},
+			},
+		},
+		{
+			name: "rate skips samples sharing a timestamp",
+			conf: Configuration{Interval: "1m"},
+			data: duplicated,
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0.5},
+			},
+		},
+		{
+			name: "unsorted data",
+			conf: Configuration{},
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(10, 0), Value: 1},
+				{Timestamp: time.Unix(0, 0), Value: 2},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, err := New(&tt.conf)
+			assert.NoError(t, err)
+			result, err := rr.Reduce(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
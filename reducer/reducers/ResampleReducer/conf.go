@@ -0,0 +1,20 @@
+package resamplereducer
+
+import (
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+)
+
+// Configuration holds the step of the output grid, the interpolation method
+// ("previous", "linear", "nearest" or "spline", defaults to "previous") and the
+// maximum gap between two samples over which values are interpolated (defaults
+// to no limit). Grid points within a longer gap are skipped, or set to NaN with
+// the "nan" gaps option.
+type Configuration struct {
+	Interval string `json:"interval" required:"true" description:"Step of the grid: a duration (1m) or a calendar interval (1d, 1w, 1mo, 1y)"`
+	Method   string `json:"method" default:"previous" enum:"previous,linear,nearest,spline" description:"Interpolation method"`
+	MaxGap   string `json:"max_gap" description:"Longest gap between two samples to interpolate over, no limit when empty"`
+
+	interval.Options     `json:",squash"`
+	reducer.InputOptions `json:",squash"`
+}
@@ -0,0 +1,216 @@
+package resamplereducer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+const (
+	// MethodPrevious holds the value of the previous sample.
+	MethodPrevious = "previous"
+	// MethodLinear interpolates linearly between the surrounding samples.
+	MethodLinear = "linear"
+	// MethodNearest takes the value of the closest sample.
+	MethodNearest = "nearest"
+	// MethodSpline interpolates with a natural cubic spline going through the samples.
+	MethodSpline = "spline"
+)
+
+// New creates a new instance of ResampleReducer with the provided configuration.
+// It parses the grid step and the maximum gap and validates the method.
+//
+// Parameters:
+//   - conf: Configuration struct containing the grid step, the method and the maximum gap.
+//
+// Returns:
+//   - *ResampleReducer: A pointer to the newly created ResampleReducer instance.
+//   - error: An error if any of the configuration values is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	method := conf.Method
+	switch method {
+	case "":
+		method = MethodPrevious
+	case MethodPrevious, MethodLinear, MethodNearest, MethodSpline:
+	default:
+		return nil, fmt.Errorf("%w: invalid method: %q", reducer.ErrInvalidConfiguration, conf.Method)
+	}
+
+	var maxGap time.Duration
+	if conf.MaxGap != "" {
+		maxGap, err = time.ParseDuration(conf.MaxGap)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid max gap: %w", reducer.ErrInvalidConfiguration, err)
+		}
+		if maxGap <= 0 {
+			return nil, fmt.Errorf("%w: max gap must be positive, got %v", reducer.ErrInvalidConfiguration, maxGap)
+		}
+	}
+
+	switch conf.Gaps {
+	case "", interval.GapsSkip, interval.GapsNaN:
+	default:
+		return nil, fmt.Errorf("%w: gaps must be %q or %q, got %q", reducer.ErrInvalidConfiguration, interval.GapsSkip, interval.GapsNaN, conf.Gaps)
+	}
+
+	// Points are stamped with the grid times
+	if err := conf.Options.RejectLabel(); err != nil {
+		return nil, err
+	}
+
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResampleReducer{
+		Interval: spec,
+		Method:   method,
+		MaxGap:   maxGap,
+		NaN:      conf.Gaps == interval.GapsNaN,
+		Input:    input,
+	}, nil
+}
+
+// ResampleReducer resamples data on a regular grid, filling the gaps of the
+// source or upsampling it by interpolating between its samples.
+type ResampleReducer struct {
+	Interval interval.Spec // Step of the grid
+	Method   string
+	MaxGap   time.Duration // Zero for no limit
+	NaN      bool          // Emit NaN rather than nothing within longer gaps
+	Input    reducer.Input
+}
+
+// Reduce returns a point for each step of the grid between the first and the
+// last point, the grid starting at the first point unless it is aligned.
+// Values are interpolated between the samples surrounding each grid point;
+// grid points within a gap longer than MaxGap are skipped, or set to NaN.
+// Under the NaNGap policy, grid points on a NaN sample or between two samples
+// one of which is NaN are handled as within a gap.
+// Points sharing a timestamp are collapsed into the last one.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+//
+// Parameters:
+//   - data: A slice of TimePoint to be resampled.
+//
+// Returns:
+//   - A slice of TimePoint on the grid.
+//   - An error if the input data is empty or unsorted.
+func (rr *ResampleReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return rr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (rr *ResampleReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	if len(data) == 0 {
+		return nil, reducer.ErrNoData
+	}
+	if err := rr.Interval.Validate(); err != nil {
+		return nil, err
+	}
+	prepared, err := rr.Input.PrepareSignal(data)
+	if err != nil {
+		return nil, err
+	}
+	// Indices in prepared only refer to data when no point was dropped or merged
+	unknownIndex := len(prepared) != len(data)
+	data = prepared
+
+	points := make([]datapoint.TimePoint, 0, len(data))
+	for i, point := range data {
+		if err := reducer.CheckContext(ctx, i); err != nil {
+			return nil, err
+		}
+		if i > 0 && point.Timestamp.Before(data[i-1].Timestamp) {
+			index := i
+			if unknownIndex {
+				index = -1
+			}
+			return nil, &reducer.Error{Index: index, Timestamp: point.Timestamp, Err: reducer.ErrUnsorted}
+		}
+		if n := len(points); n > 0 && point.Timestamp.Equal(points[n-1].Timestamp) {
+			points[n-1] = point
+			continue
+		}
+		points = append(points, point)
+	}
+
+	var s *spline
+	if rr.Method == MethodSpline {
+		s = newSpline(points, rr.isGap)
+	}
+
+	var reduced []datapoint.TimePoint
+	first, last := points[0].Timestamp, points[len(points)-1].Timestamp
+	t := rr.Interval.Start(first)
+	if t.Before(first) {
+		t = rr.Interval.Next(t)
+	}
+	i := 0 // Index of the last sample not after t
+	for ; !t.After(last); t = rr.Interval.Next(t) {
+		if err := reducer.CheckContext(ctx, len(reduced)); err != nil {
+			return nil, err
+		}
+		for i+1 < len(points) && !points[i+1].Timestamp.After(t) {
+			i++
+		}
+		if points[i].Timestamp.Equal(t) {
+			if rr.Input.NaN == reducer.NaNGap && math.IsNaN(points[i].Value) {
+				if rr.NaN {
+					reduced = append(reduced, datapoint.TimePoint{Timestamp: t, Value: math.NaN()})
+				}
+				continue
+			}
+			reduced = append(reduced, datapoint.TimePoint{Timestamp: t, Value: points[i].Value})
+			continue
+		}
+
+		a, b := points[i], points[i+1]
+		if rr.isGap(a, b) {
+			if rr.NaN {
+				reduced = append(reduced, datapoint.TimePoint{Timestamp: t, Value: math.NaN()})
+			}
+			continue
+		}
+
+		var value float64
+		switch rr.Method {
+		case MethodLinear:
+			ratio := float64(t.Sub(a.Timestamp)) / float64(b.Timestamp.Sub(a.Timestamp))
+			value = a.Value + ratio*(b.Value-a.Value)
+		case MethodNearest:
+			value = a.Value
+			if b.Timestamp.Sub(t) < t.Sub(a.Timestamp) {
+				value = b.Value
+			}
+		case MethodSpline:
+			value = s.at(i, t)
+		default:
+			value = a.Value
+		}
+		reduced = append(reduced, datapoint.TimePoint{Timestamp: t, Value: value})
+	}
+
+	return reduced, nil
+}
+
+// isGap reports whether the values between the consecutive samples a and b are
+// unknown, because they are more than MaxGap apart or one of them is NaN under
+// the NaNGap policy.
+func (rr *ResampleReducer) isGap(a, b datapoint.TimePoint) bool {
+	if rr.MaxGap > 0 && b.Timestamp.Sub(a.Timestamp) > rr.MaxGap {
+		return true
+	}
+	return rr.Input.NaN == reducer.NaNGap && (math.IsNaN(a.Value) || math.IsNaN(b.Value))
+}
@@ -0,0 +1,276 @@
+package resamplereducer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expectErr bool
+	}{
+		{
+			name: "valid configuration",
+			conf: Configuration{Interval: "1m"},
+		},
+		{
+			name: "spline with max gap",
+			conf: Configuration{Interval: "1m", Method: MethodSpline, MaxGap: "10m", Options: interval.Options{Gaps: interval.GapsNaN}},
+		},
+		{
+			name:      "invalid interval",
+			conf:      Configuration{Interval: "invalid"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid method",
+			conf:      Configuration{Interval: "1m", Method: "cubic"},
+			expectErr: true,
+		},
+		{
+			name:      "unsupported label",
+			conf:      Configuration{Interval: "1m", Options: interval.Options{Label: interval.LabelCenter}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid max gap",
+			conf:      Configuration{Interval: "1m", MaxGap: "long"},
+			expectErr: true,
+		},
+		{
+			name:      "negative max gap",
+			conf:      Configuration{Interval: "1m", MaxGap: "-5m"},
+			expectErr: true,
+		},
+		{
+			name:      "unsupported gaps",
+			conf:      Configuration{Interval: "1m", Options: interval.Options{Gaps: interval.GapsLinear}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	minute := func(m float64) time.Time {
+		return time.Unix(int64(m*60), 0).UTC()
+	}
+	data := []datapoint.TimePoint{
+		{Timestamp: minute(0), Value: 0},
+		{Timestamp: minute(2), Value: 4},
+		{Timestamp: minute(3), Value: 1},
+	}
+
+	tests := []struct {
+		name      string
+		conf      Configuration
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		expectErr bool
+	}{
+		{
+			name:      "empty data",
+			conf:      Configuration{Interval: "1m"},
+			data:      []datapoint.TimePoint{},
+			expectErr: true,
+		},
+		{
+			name: "unsorted data",
+			conf: Configuration{Interval: "1m"},
+			data: []datapoint.TimePoint{
+				{Timestamp: minute(1), Value: 1},
+				{Timestamp: minute(0), Value: 0},
+			},
+			expectErr: true,
+		},
+		{
+			name: "previous",
+			conf: Configuration{Interval: "30s"},
+			data: data,
+			expected: []datapoint.TimePoint{
+				{Timestamp: minute(0), Value: 0},
+				{Timestamp: minute(0.5), Value: 0},
+				{Timestamp: minute(1), Value: 0},
+				{Timestamp: minute(1.5), Value: 0},
+				{Timestamp: minute(2), Value: 4},
+				{Timestamp: minute(2.5), Value: 4},
+				{Timestamp: minute(3), Value: 1},
+			},
+		},
+		{
+			name: "linear",
+			conf: Configuration{Interval: "30s", Method: MethodLinear},
+			data: data,
+			expected: []datapoint.TimePoint{
+				{Timestamp: minute(0), Value: 0},
+				{Timestamp: minute(0.5), Value: 1},
+				{Timestamp: minute(1), Value: 2},
+				{Timestamp: minute(1.5), Value: 3},
+				{Timestamp: minute(2), Value: 4},
+				{Timestamp: minute(2.5), Value: 2.5},
+				{Timestamp: minute(3), Value: 1},
+			},
+		},
+		{
+			name: "nearest",
+			conf: Configuration{Interval: "30s", Method: MethodNearest},
+			data: data,
+			expected: []datapoint.TimePoint{
+				{Timestamp: minute(0), Value: 0},
+				{Timestamp: minute(0.5), Value: 0},
+				{Timestamp: minute(1), Value: 0},
+				{Timestamp: minute(1.5), Value: 4},
+				{Timestamp: minute(2), Value: 4},
+				{Timestamp: minute(2.5), Value: 4},
+				{Timestamp: minute(3), Value: 1},
+			},
+		},
+		{
+			name: "spline",
+			conf: Configuration{Interval: "30s", Method: MethodSpline},
+			data: []datapoint.TimePoint{
+				{Timestamp: minute(0), Value: 0},
+				{Timestamp: minute(1), Value: 1},
+				{Timestamp: minute(2), Value: 0},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: minute(0), Value: 0},
+				{Timestamp: minute(0.5), Value: 0.6875},
+				{Timestamp: minute(1), Value: 1},
+				{Timestamp: minute(1.5), Value: 0.6875},
+				{Timestamp: minute(2), Value: 0},
+			},
+		},
+		{
+			name: "aligned grid",
+			conf: Configuration{Interval: "1m", Method: MethodLinear, Options: interval.Options{Align: interval.AlignEpoch}},
+			data: []datapoint.TimePoint{
+				{Timestamp: minute(0.5), Value: 1},
+				{Timestamp: minute(2.5), Value: 5},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: minute(1), Value: 2},
+				{Timestamp: minute(2), Value: 4},
+			},
+		},
+		{
+			name: "duplicate timestamps",
+			conf: Configuration{Interval: "1m", Method: MethodLinear},
+			data: []datapoint.TimePoint{
+				{Timestamp: minute(0), Value: 1},
+				{Timestamp: minute(0), Value: 2},
+				{Timestamp: minute(2), Value: 4},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: minute(0), Value: 2},
+				{Timestamp: minute(1), Value: 3},
+				{Timestamp: minute(2), Value: 4},
+			},
+		},
+		{
+			name: "gap longer than max gap skipped",
+			conf: Configuration{Interval: "1m", Method: MethodLinear, MaxGap: "2m"},
+			data: []datapoint.TimePoint{
+				{Timestamp: minute(0), Value: 0},
+				{Timestamp: minute(1), Value: 1},
+				{Timestamp: minute(4), Value: 4},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: minute(0), Value: 0},
+				{Timestamp: minute(1), Value: 1},
+				{Timestamp: minute(4), Value: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(&tt.conf)
+			assert.NoError(t, err)
+			result, err := r.Reduce(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, result, len(tt.expected))
+			for i := range tt.expected {
+				assert.Equal(t, tt.expected[i].Timestamp, result[i].Timestamp)
+				assert.InDelta(t, tt.expected[i].Value, result[i].Value, 1e-9)
+			}
+		})
+	}
+}
+
+func TestReduce_MaxGapNaN(t *testing.T) {
+	r, err := New(&Configuration{Interval: "1m", MaxGap: "2m", Options: interval.Options{Gaps: interval.GapsNaN}})
+	assert.NoError(t, err)
+
+	result, err := r.Reduce([]datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(180, 0), Value: 2},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result, 4)
+	assert.Equal(t, 1.0, result[0].Value)
+	assert.True(t, math.IsNaN(result[1].Value))
+	assert.True(t, math.IsNaN(result[2].Value))
+	assert.Equal(t, 2.0, result[3].Value)
+}
+
+func TestReduce_NaNGap(t *testing.T) {
+	r, err := New(&Configuration{
+		Interval:     "1m",
+		Method:       MethodLinear,
+		Options:      interval.Options{Gaps: interval.GapsNaN},
+		InputOptions: reducer.InputOptions{NaN: reducer.NaNGap},
+	})
+	assert.NoError(t, err)
+
+	result, err := r.Reduce([]datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 0},
+		{Timestamp: time.Unix(120, 0), Value: 2},
+		{Timestamp: time.Unix(180, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(300, 0), Value: 5},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result, 6)
+	assert.Equal(t, []float64{0, 1, 2}, []float64{result[0].Value, result[1].Value, result[2].Value})
+	assert.True(t, math.IsNaN(result[3].Value), "the NaN sample is a gap")
+	assert.True(t, math.IsNaN(result[4].Value), "the segment from the NaN sample is a gap")
+	assert.Equal(t, 5.0, result[5].Value)
+}
+
+func TestReduce_SplineReproducesLines(t *testing.T) {
+	r, err := New(&Configuration{Interval: "10s", Method: MethodSpline})
+	assert.NoError(t, err)
+
+	var data []datapoint.TimePoint
+	for _, s := range []int64{0, 30, 50, 120, 130} {
+		data = append(data, datapoint.TimePoint{Timestamp: time.Unix(s, 0), Value: 2*float64(s) + 1})
+	}
+	result, err := r.Reduce(data)
+	assert.NoError(t, err)
+	assert.Len(t, result, 14)
+	for _, point := range result {
+		assert.InDelta(t, 2*float64(point.Timestamp.Unix())+1, point.Value, 1e-9)
+	}
+}
@@ -0,0 +1,74 @@
+package resamplereducer
+
+import (
+	"time"
+
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// spline is a natural cubic spline going through a series of points. The
+// series is split into independent runs at each gap between two samples, each
+// run having zero curvature at both ends.
+type spline struct {
+	points []datapoint.TimePoint
+	second []float64 // Second derivative at each point, per second squared
+}
+
+// newSpline computes the spline going through points, which must have strictly
+// increasing timestamps. isGap reports whether two consecutive points are
+// separated by a gap.
+func newSpline(points []datapoint.TimePoint, isGap func(a, b datapoint.TimePoint) bool) *spline {
+	s := &spline{points: points, second: make([]float64, len(points))}
+	lo := 0
+	for i := 1; i <= len(points); i++ {
+		if i == len(points) || isGap(points[i-1], points[i]) {
+			s.solve(lo, i)
+			lo = i
+		}
+	}
+	return s
+}
+
+// solve computes the second derivatives of the run points[lo:hi] by solving
+// its tridiagonal system with the Thomas algorithm.
+func (s *spline) solve(lo, hi int) {
+	n := hi - lo
+	if n < 3 {
+		return
+	}
+	h := func(i int) float64 {
+		return s.points[i+1].Timestamp.Sub(s.points[i].Timestamp).Seconds()
+	}
+	slope := func(i int) float64 {
+		return (s.points[i+1].Value - s.points[i].Value) / h(i)
+	}
+
+	// Forward elimination over the interior points, the ends being zero
+	diag := make([]float64, n)
+	rhs := make([]float64, n)
+	for i := lo + 1; i < hi-1; i++ {
+		k := i - lo
+		diag[k] = 2 * (h(i-1) + h(i))
+		rhs[k] = 6 * (slope(i) - slope(i-1))
+		if k > 1 {
+			factor := h(i-1) / diag[k-1]
+			diag[k] -= factor * h(i-1)
+			rhs[k] -= factor * rhs[k-1]
+		}
+	}
+	// Back substitution
+	for i := hi - 2; i > lo; i-- {
+		k := i - lo
+		s.second[i] = (rhs[k] - h(i)*s.second[i+1]) / diag[k]
+	}
+}
+
+// at returns the value of the spline at t, which lies between points[i] and points[i+1].
+func (s *spline) at(i int, t time.Time) float64 {
+	a, b := s.points[i], s.points[i+1]
+	h := b.Timestamp.Sub(a.Timestamp).Seconds()
+	wa := b.Timestamp.Sub(t).Seconds() / h
+	wb := t.Sub(a.Timestamp).Seconds() / h
+	return wa*a.Value + wb*b.Value +
+		((wa*wa*wa-wa)*s.second[i]+(wb*wb*wb-wb)*s.second[i+1])*h*h/6
+}
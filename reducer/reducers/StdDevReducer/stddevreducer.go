@@ -0,0 +1,75 @@
+package stddevreducer
+
+import (
+	"context"
+	"math"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	variancereducer "github.com/EcoPowerHub/dustbuster/reducer/reducers/VarianceReducer"
+)
+
+// New creates a new instance of StdDevReducer with the provided configuration.
+// It parses the interval, either a duration or a calendar interval, and returns an error if it is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StdDevReducer{
+		Interval: spec,
+		Input:    input,
+	}, nil
+}
+
+// StdDevReducer reduces data by computing the population standard deviation over fixed intervals.
+type StdDevReducer struct {
+	Interval interval.Spec
+	Input    reducer.Input
+}
+
+// Reduce takes a slice of TimePoint data and returns, for each interval holding
+// data, the population standard deviation of the values, stamped with the interval
+// label. The first interval holds the first point and empty intervals follow the
+// gaps option. An interval holding only equal values, infinite ones included,
+// has a zero standard deviation.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (sr *StdDevReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return sr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (sr *StdDevReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return sr.Input.Reduce(ctx, sr.newStream(), data)
+}
+
+// NewStream returns a StreamReducer computing the population standard deviation of
+// the pushed points over the reducer's interval.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (sr *StdDevReducer) NewStream() reducer.StreamReducer {
+	return sr.Input.Stream(sr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (sr *StdDevReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(sr.Interval, &stddevAggregator{})
+}
+
+// stddevAggregator takes the square root of the variance of an interval.
+type stddevAggregator struct {
+	variance variancereducer.Aggregator
+}
+
+func (a *stddevAggregator) Add(point datapoint.TimePoint) {
+	a.variance.Add(point)
+}
+
+func (a *stddevAggregator) Value() float64 {
+	return math.Sqrt(a.variance.Value())
+}
@@ -0,0 +1,125 @@
+package stddevreducer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expectErr bool
+	}{
+		{
+			name: "valid interval",
+			conf: Configuration{Interval: "1m"},
+		},
+		{
+			name:      "invalid interval",
+			conf:      Configuration{Interval: "invalid"},
+			expectErr: true,
+		},
+		{
+			name:      "negative interval",
+			conf:      Configuration{Interval: "-1m"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		expectErr bool
+	}{
+		{
+			name:      "empty data",
+			data:      []datapoint.TimePoint{},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name: "single point",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 42},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0},
+			},
+		},
+		{
+			name: "multiple intervals",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 2},
+				{Timestamp: time.Unix(10, 0), Value: 4},
+				{Timestamp: time.Unix(20, 0), Value: 4},
+				{Timestamp: time.Unix(30, 0), Value: 4},
+				{Timestamp: time.Unix(40, 0), Value: 5},
+				{Timestamp: time.Unix(50, 0), Value: 5},
+				{Timestamp: time.Unix(51, 0), Value: 7},
+				{Timestamp: time.Unix(52, 0), Value: 9},
+				{Timestamp: time.Unix(60, 0), Value: 3},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 2},
+				{Timestamp: time.Unix(60, 0), Value: 0},
+			},
+		},
+		{
+			name: "empty intervals are skipped",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 1},
+				{Timestamp: time.Unix(150, 0), Value: 1},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0},
+				{Timestamp: time.Unix(120, 0), Value: 0},
+			},
+		},
+		{
+			name: "equal infinite values",
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: math.Inf(1)},
+				{Timestamp: time.Unix(10, 0), Value: math.Inf(1)},
+				{Timestamp: time.Unix(60, 0), Value: math.Inf(-1)},
+				{Timestamp: time.Unix(70, 0), Value: math.Inf(-1)},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 0},
+				{Timestamp: time.Unix(60, 0), Value: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &StdDevReducer{Interval: interval.Fixed(time.Minute)}
+			result, err := r.Reduce(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
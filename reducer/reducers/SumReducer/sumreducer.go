@@ -1,78 +1,93 @@
 package sumreducer
 
 import (
-	"errors"
-	"fmt"
-	"sort"
+	"context"
 	"time"
 
 	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 )
 
 func New(conf *Configuration) (reducer.DataReducer, error) {
-	interval, err := time.ParseDuration(conf.Interval)
+	opts := conf.Options
+	if opts.Gaps == "" {
+		// Empty intervals have always been summed to zero
+		opts.Gaps = interval.GapsZero
+	}
+	spec, err := interval.Parse(conf.Interval, opts)
 	if err != nil {
-		return nil, fmt.Errorf("invalid interval: %w", err)
+		return nil, err
 	}
 
-	if interval <= 0 {
-		return nil, fmt.Errorf("interval must be positive, got %v", interval)
+	// Unsorted input has always been sorted before being summed
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderSort)
+	if err != nil {
+		return nil, err
 	}
 
 	return &SumReducer{
-		Interval: interval,
+		Interval: spec.Duration(),
+		Input:    input,
+		spec:     spec,
 	}, nil
 }
 
 type SumReducer struct {
-	Interval time.Duration
+	Interval time.Duration // Length of fixed intervals, zero for calendar intervals
+	Input    reducer.Input
+	spec     interval.Spec // Intervals parsed by New, along with their options
 }
 
 func (sr *SumReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
-	if len(data) == 0 {
-		return nil, errors.New("no data to reduce")
+	return sr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (sr *SumReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	input := sr.Input
+	if input.Order == "" {
+		// Unsorted input has always been sorted before being summed
+		input.Order = reducer.OrderSort
+	}
+	return input.Reduce(ctx, sr.newStream(), data)
+}
+
+// intervals returns the intervals parsed by New, or the fixed intervals of
+// Interval when the reducer was built as a struct literal.
+func (sr *SumReducer) intervals() interval.Spec {
+	if !sr.spec.IsZero() {
+		return sr.spec
 	}
+	// Empty intervals have always been summed to zero. An invalid Interval
+	// yields the zero Spec, which the stream rejects.
+	spec, _ := interval.Parse(sr.Interval.String(), interval.Options{Gaps: interval.GapsZero})
+	return spec
+}
 
-	// Sort data by timestamp to handle unsorted input
-	sort.Slice(data, func(i, j int) bool {
-		return data[i].Timestamp.Before(data[j].Timestamp)
-	})
+// NewStream returns a StreamReducer summing the pushed points over the reducer's interval.
+// Unlike Reduce, the stream cannot sort its input: points must be pushed in timestamp order.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (sr *SumReducer) NewStream() reducer.StreamReducer {
+	return sr.Input.Stream(sr.newStream())
+}
 
-	var reduced []datapoint.TimePoint
-	var sum float64
-	startTime := data[0].Timestamp
+// newStream returns the stream reducing the points left by the input policies.
+func (sr *SumReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(sr.intervals(), &sumAggregator{})
+}
 
-	for _, point := range data {
-		if point.Timestamp.Before(startTime.Add(sr.Interval)) {
-			sum += point.Value
-		} else {
-			// Append the summed value for the current interval
-			reduced = append(reduced, datapoint.TimePoint{
-				Timestamp: startTime,
-				Value:     sum,
-			})
-			// Move to the next interval
-			for startTime.Add(sr.Interval).Before(point.Timestamp) {
-				startTime = startTime.Add(sr.Interval)
-				reduced = append(reduced, datapoint.TimePoint{
-					Timestamp: startTime,
-					Value:     0,
-				})
-			}
-			// Start accumulating for the new interval
-			startTime = startTime.Add(sr.Interval)
-			sum = point.Value
-		}
-	}
+// sumAggregator holds the state of the interval currently being summed.
+type sumAggregator struct {
+	sum float64
+}
 
-	// Append the final interval's sum
-	if sum > 0 {
-		reduced = append(reduced, datapoint.TimePoint{
-			Timestamp: startTime,
-			Value:     sum,
-		})
-	}
+func (a *sumAggregator) Add(point datapoint.TimePoint) {
+	a.sum += point.Value
+}
 
-	return reduced, nil
+func (a *sumAggregator) Value() float64 {
+	value := a.sum
+	a.sum = 0
+	return value
 }
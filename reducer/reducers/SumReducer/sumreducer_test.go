@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
 	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
 	"github.com/stretchr/testify/assert"
 )
@@ -49,3 +51,134 @@ func TestReduce(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, reduced)
 }
+
+func TestNewStream(t *testing.T) {
+	sr, err := New(&Configuration{Interval: "1m"})
+	assert.NoError(t, err)
+	stream := sr.(reducer.Streamer).NewStream()
+
+	out, err := stream.Push(datapoint.TimePoint{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 1.0})
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	// A point three intervals later closes the first one
+	out, err = stream.Push(datapoint.TimePoint{Timestamp: time.Date(2023, 10, 1, 0, 3, 0, 0, time.UTC), Value: 2.0})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 1.0}}, out)
+
+	// The gap is filled with zeros along with the next interval holding data
+	out, err = stream.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Date(2023, 10, 1, 0, 1, 0, 0, time.UTC), Value: 0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 2, 0, 0, time.UTC), Value: 0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 3, 0, 0, time.UTC), Value: 2.0},
+	}, out)
+}
+
+func TestReduce_Label(t *testing.T) {
+	sr, err := New(&Configuration{Interval: "1m", Options: interval.Options{Label: interval.LabelEnd}})
+	assert.NoError(t, err)
+
+	reduced, err := sr.Reduce([]datapoint.TimePoint{
+		{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 1.0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 0, 30, 0, time.UTC), Value: 2.0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 2, 0, 0, time.UTC), Value: 3.0},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Date(2023, 10, 1, 0, 1, 0, 0, time.UTC), Value: 3.0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 2, 0, 0, time.UTC), Value: 0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 3, 0, 0, time.UTC), Value: 3.0},
+	}, reduced)
+}
+
+func TestReduce_Gaps(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 1.0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 2, 0, 0, time.UTC), Value: -2.0},
+	}
+
+	// Empty intervals are summed to zero by default, and a final interval
+	// summing to a negative value is kept
+	sr, err := New(&Configuration{Interval: "1m"})
+	assert.NoError(t, err)
+	reduced, err := sr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 1.0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 1, 0, 0, time.UTC), Value: 0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 2, 0, 0, time.UTC), Value: -2.0},
+	}, reduced)
+
+	sr, err = New(&Configuration{Interval: "1m", Options: interval.Options{Gaps: interval.GapsSkip}})
+	assert.NoError(t, err)
+	reduced, err = sr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Value: 1.0},
+		{Timestamp: time.Date(2023, 10, 1, 0, 2, 0, 0, time.UTC), Value: -2.0},
+	}, reduced)
+}
+
+func TestReduce_Order(t *testing.T) {
+	unsorted := func() []datapoint.TimePoint {
+		return []datapoint.TimePoint{
+			{Timestamp: time.Unix(70, 0), Value: 3},
+			{Timestamp: time.Unix(0, 0), Value: 1},
+			{Timestamp: time.Unix(30, 0), Value: 2},
+		}
+	}
+
+	// Unsorted input is summed from a sorted copy by default
+	sr, err := New(&Configuration{Interval: "1m"})
+	assert.NoError(t, err)
+	data := unsorted()
+	result, err := sr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 3},
+		{Timestamp: time.Unix(60, 0), Value: 3},
+	}, result)
+	assert.Equal(t, unsorted(), data)
+
+	sr, err = New(&Configuration{Interval: "1m", InputOptions: reducer.InputOptions{Order: reducer.OrderReject}})
+	assert.NoError(t, err)
+	_, err = sr.Reduce(data)
+	assert.ErrorIs(t, err, reducer.ErrUnsorted)
+}
+
+func TestReduce_Duplicates(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(30, 0), Value: 2},
+		{Timestamp: time.Unix(30, 0), Value: 2},
+	}
+
+	// Duplicates are summed unless a policy merges them first
+	sr, err := New(&Configuration{Interval: "1m"})
+	assert.NoError(t, err)
+	result, err := sr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 5}}, result)
+
+	sr, err = New(&Configuration{Interval: "1m", InputOptions: reducer.InputOptions{Duplicates: reducer.DuplicatesFirst}})
+	assert.NoError(t, err)
+	result, err = sr.Reduce(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{{Timestamp: time.Unix(0, 0), Value: 3}}, result)
+}
+
+func TestReduce_StructLiteral(t *testing.T) {
+	sr := &SumReducer{Interval: time.Minute}
+	reduced, err := sr.Reduce([]datapoint.TimePoint{
+		{Timestamp: time.Unix(150, 0), Value: 2},
+		{Timestamp: time.Unix(0, 0), Value: 1},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(60, 0), Value: 0},
+		{Timestamp: time.Unix(120, 0), Value: 2},
+	}, reduced, "unsorted input is sorted and empty intervals are summed to zero")
+}
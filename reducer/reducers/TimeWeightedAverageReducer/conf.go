@@ -0,0 +1,16 @@
+package timeweightedaveragereducer
+
+import (
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+)
+
+// Configuration holds the interval and the interpolation method ("step" or
+// "linear", defaults to "step") used between two samples.
+type Configuration struct {
+	Interval string `json:"interval" required:"true" description:"Length of the intervals: a duration (15m) or a calendar interval (1d, 1w, 1mo, 1y)"`
+	Method   string `json:"method" default:"step" enum:"step,linear" description:"Interpolation between two samples"`
+
+	interval.Options     `json:",squash"`
+	reducer.InputOptions `json:",squash"`
+}
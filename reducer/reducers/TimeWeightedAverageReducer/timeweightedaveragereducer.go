@@ -0,0 +1,132 @@
+package timeweightedaveragereducer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+const (
+	// MethodStep holds each value until the next sample.
+	MethodStep = "step"
+	// MethodLinear interpolates linearly between two consecutive samples.
+	MethodLinear = "linear"
+)
+
+// New creates a new instance of TimeWeightedAverageReducer with the provided configuration.
+// It parses the interval and validates the interpolation method.
+//
+// Parameters:
+//   - conf: Configuration struct containing the interval and the interpolation method.
+//
+// Returns:
+//   - *TimeWeightedAverageReducer: A pointer to the newly created TimeWeightedAverageReducer instance.
+//   - error: An error if the interval or the method is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+	// The signal is integrated over every interval, none of them is empty
+	if err := conf.Options.RejectGaps(); err != nil {
+		return nil, err
+	}
+	method := conf.Method
+	switch method {
+	case "":
+		method = MethodStep
+	case MethodStep, MethodLinear:
+	default:
+		return nil, fmt.Errorf("%w: invalid method: %q", reducer.ErrInvalidConfiguration, conf.Method)
+	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TimeWeightedAverageReducer{
+		Interval: spec,
+		Method:   method,
+		Input:    input,
+	}, nil
+}
+
+// TimeWeightedAverageReducer reduces data by averaging the signal over fixed
+// intervals, each value being weighted by the time it was held. Unlike
+// AverageReducer, it is not biased by irregular sampling.
+type TimeWeightedAverageReducer struct {
+	Interval interval.Spec
+	Method   string
+	Input    reducer.Input
+}
+
+// Reduce takes a slice of TimePoint data and returns the time-weighted average
+// of the signal over each interval, the first interval holding the first
+// point. The signal is defined between the first and the last point: a bucket
+// without samples still gets the value carried over from the previous sample.
+// A bucket holding only the last point gets that point's value.
+// Under the NaNGap policy, the signal is undefined on the segments going to or
+// from a NaN value: they are left out, and a bucket covered only by them is NaN.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (tr *TimeWeightedAverageReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return tr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (tr *TimeWeightedAverageReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return tr.Input.ReduceSignal(ctx, tr.newStream(), data)
+}
+
+// NewStream returns a StreamReducer computing the time-weighted average of the pushed points.
+// Pushing a point older than the previous one returns an error.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (tr *TimeWeightedAverageReducer) NewStream() reducer.StreamReducer {
+	return tr.Input.StreamSignal(tr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (tr *TimeWeightedAverageReducer) newStream() reducer.StreamReducer {
+	return &twaStream{
+		spec:       tr.Interval,
+		integrator: interval.NewIntegrator(tr.Interval, tr.Method == MethodLinear, tr.Input.NaN == reducer.NaNGap),
+	}
+}
+
+// twaStream averages the integral of the signal over each interval.
+type twaStream struct {
+	spec       interval.Spec
+	integrator *interval.Integrator
+}
+
+// Push integrates the segment between the previous point and this one,
+// returning the average of every interval the segment closes.
+func (s *twaStream) Push(point datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	integrals, err := s.integrator.Push(point)
+	if err != nil {
+		return nil, err
+	}
+	var reduced []datapoint.TimePoint
+	for _, integral := range integrals {
+		reduced = append(reduced, datapoint.TimePoint{
+			Timestamp: s.spec.Label(integral.Start),
+			Value:     integral.Area / float64(integral.Covered),
+		})
+	}
+	return reduced, nil
+}
+
+// Flush returns the average of the last interval.
+func (s *twaStream) Flush() ([]datapoint.TimePoint, error) {
+	integral, last, ok := s.integrator.Flush()
+	if !ok {
+		return nil, nil
+	}
+	value := last.Value
+	if integral.Covered > 0 {
+		value = integral.Area / float64(integral.Covered)
+	}
+	return []datapoint.TimePoint{{Timestamp: s.spec.Label(integral.Start), Value: value}}, nil
+}
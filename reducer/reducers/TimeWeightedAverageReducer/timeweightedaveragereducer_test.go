@@ -0,0 +1,182 @@
+package timeweightedaveragereducer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Configuration
+		expectErr bool
+	}{
+		{
+			name: "default method",
+			conf: Configuration{Interval: "1m"},
+		},
+		{
+			name: "linear method",
+			conf: Configuration{Interval: "1m", Method: MethodLinear},
+		},
+		{
+			name:      "invalid interval",
+			conf:      Configuration{Interval: "invalid"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid method",
+			conf:      Configuration{Interval: "1m", Method: "cubic"},
+			expectErr: true,
+		},
+		{
+			name:      "unsupported gaps",
+			conf:      Configuration{Interval: "1m", Options: interval.Options{Gaps: interval.GapsNaN}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.conf)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		data      []datapoint.TimePoint
+		expected  []datapoint.TimePoint
+		expectErr bool
+	}{
+		{
+			name:      "empty data",
+			method:    MethodStep,
+			data:      []datapoint.TimePoint{},
+			expectErr: true,
+		},
+		{
+			name:   "single point",
+			method: MethodStep,
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 42},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 42},
+			},
+		},
+		{
+			name:   "step weights values by holding time",
+			method: MethodStep,
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 10},
+				{Timestamp: time.Unix(45, 0), Value: 30},
+				{Timestamp: time.Unix(50, 0), Value: 30},
+				{Timestamp: time.Unix(55, 0), Value: 30},
+				{Timestamp: time.Unix(90, 0), Value: 0},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 15},
+				{Timestamp: time.Unix(60, 0), Value: 30},
+			},
+		},
+		{
+			name:   "linear interpolation",
+			method: MethodLinear,
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 10},
+				{Timestamp: time.Unix(30, 0), Value: 20},
+				{Timestamp: time.Unix(90, 0), Value: 0},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 15},
+				{Timestamp: time.Unix(60, 0), Value: 5},
+			},
+		},
+		{
+			name:   "value carried through empty intervals",
+			method: MethodStep,
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 10},
+				{Timestamp: time.Unix(150, 0), Value: 20},
+			},
+			expected: []datapoint.TimePoint{
+				{Timestamp: time.Unix(0, 0), Value: 10},
+				{Timestamp: time.Unix(60, 0), Value: 10},
+				{Timestamp: time.Unix(120, 0), Value: 10},
+			},
+		},
+		{
+			name:   "unsorted data",
+			method: MethodStep,
+			data: []datapoint.TimePoint{
+				{Timestamp: time.Unix(30, 0), Value: 10},
+				{Timestamp: time.Unix(0, 0), Value: 20},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TimeWeightedAverageReducer{Interval: interval.Fixed(time.Minute), Method: tt.method}
+			result, err := tr.Reduce(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestReduce_NaN(t *testing.T) {
+	data := []datapoint.TimePoint{
+		{Timestamp: time.Unix(0, 0), Value: 0},
+		{Timestamp: time.Unix(30, 0), Value: 10},
+		{Timestamp: time.Unix(45, 0), Value: math.NaN()},
+		{Timestamp: time.Unix(60, 0), Value: 20},
+		{Timestamp: time.Unix(120, 0), Value: 20},
+	}
+	tests := []struct {
+		nan   string
+		first float64
+	}{
+		{nan: reducer.NaNSkip, first: 10},
+		{nan: reducer.NaNGap, first: 5},
+		{nan: reducer.NaNPropagate, first: math.NaN()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nan, func(t *testing.T) {
+			tr, err := New(&Configuration{Interval: "1m", Method: MethodLinear, InputOptions: reducer.InputOptions{NaN: tt.nan}})
+			assert.NoError(t, err)
+			result, err := tr.Reduce(data)
+			assert.NoError(t, err)
+			assert.Len(t, result, 3)
+			if math.IsNaN(tt.first) {
+				assert.True(t, math.IsNaN(result[0].Value))
+			} else {
+				assert.Equal(t, tt.first, result[0].Value)
+			}
+			assert.Equal(t, []datapoint.TimePoint{
+				{Timestamp: time.Unix(60, 0), Value: 20},
+				{Timestamp: time.Unix(120, 0), Value: 20},
+			}, result[1:])
+		})
+	}
+}
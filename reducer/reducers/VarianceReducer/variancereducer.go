@@ -0,0 +1,96 @@
+package variancereducer
+
+import (
+	"context"
+	"math"
+
+	"github.com/EcoPowerHub/dustbuster/reducer"
+	"github.com/EcoPowerHub/dustbuster/reducer/interval"
+	datapoint "github.com/EcoPowerHub/dustbuster/reducer/point"
+)
+
+// New creates a new instance of VarianceReducer with the provided configuration.
+// It parses the interval, either a duration or a calendar interval, and returns an error if it is invalid.
+func New(conf *Configuration) (reducer.DataReducer, error) {
+	spec, err := interval.Parse(conf.Interval, conf.Options)
+	if err != nil {
+		return nil, err
+	}
+	input, err := reducer.ParseInput(conf.InputOptions, reducer.OrderReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VarianceReducer{
+		Interval: spec,
+		Input:    input,
+	}, nil
+}
+
+// VarianceReducer reduces data by computing the population variance over fixed intervals.
+type VarianceReducer struct {
+	Interval interval.Spec
+	Input    reducer.Input
+}
+
+// Reduce takes a slice of TimePoint data and returns, for each interval holding
+// data, the population variance of the values, stamped with the interval label.
+// The first interval holds the first point and empty intervals follow the gaps
+// option. An interval holding only equal values, infinite ones included, has a
+// zero variance.
+// Input data not sorted by timestamp is handled according to the order policy of Input.
+func (vr *VarianceReducer) Reduce(data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return vr.ReduceContext(context.Background(), data)
+}
+
+// ReduceContext behaves like Reduce, but stops with an error wrapping ctx.Err() once ctx is done.
+func (vr *VarianceReducer) ReduceContext(ctx context.Context, data []datapoint.TimePoint) ([]datapoint.TimePoint, error) {
+	return vr.Input.Reduce(ctx, vr.newStream(), data)
+}
+
+// NewStream returns a StreamReducer computing the population variance of the
+// pushed points over the reducer's interval.
+// The NaN and duplicates policies of Input apply to the pushed points.
+func (vr *VarianceReducer) NewStream() reducer.StreamReducer {
+	return vr.Input.Stream(vr.newStream())
+}
+
+// newStream returns the stream reducing the points left by the input policies.
+func (vr *VarianceReducer) newStream() reducer.StreamReducer {
+	return interval.NewStream(vr.Interval, &Aggregator{})
+}
+
+// Aggregator computes the population variance of an interval with Welford's
+// online algorithm. It implements interval.Aggregator.
+// The variance of equal values is zero, infinite ones included, while other
+// intervals holding an infinite value have a NaN variance.
+type Aggregator struct {
+	count    float64
+	mean     float64
+	m2       float64 // Sum of squared differences from the mean
+	first    float64
+	constant bool // Whether every value equals the first one, which is not NaN
+}
+
+func (a *Aggregator) Add(point datapoint.TimePoint) {
+	a.count++
+	if a.count == 1 {
+		a.first = point.Value
+		a.constant = !math.IsNaN(point.Value)
+	} else if point.Value != a.first {
+		a.constant = false
+	}
+	delta := point.Value - a.mean
+	a.mean += delta / a.count
+	a.m2 += delta * (point.Value - a.mean)
+}
+
+func (a *Aggregator) Value() float64 {
+	variance := a.m2 / a.count
+	if a.constant {
+		// Equal infinite values would otherwise make the variance NaN
+		variance = 0
+	}
+	*a = Aggregator{}
+	return variance
+}